@@ -0,0 +1,454 @@
+package main
+
+/* This file contains an opt-in HTTP/JSON API on localhost, which allows other programs to list and manipulate the board of the active tab in the running window */
+
+
+/* ================================================================================ Imports */
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+
+/* ================================================================================ Constants */
+const (
+	DEFAULT_API_ADDRESS  = "127.0.0.1:7411"
+	API_TOKEN_HEADER     = "X-Bankan-Token"
+	API_MAX_BODY_SIZE    = 1 << 20
+	API_UI_WAIT_DURATION = 10 * time.Second
+)
+
+
+/* ================================================================================ Private types */
+type apiItem struct {
+	Id          string
+	Stage       string
+	Title       string
+	Description string
+	Tags        []string
+	Expanded    bool
+}
+
+
+type apiItemChange struct {
+	Stage       *string
+	Title       *string
+	Description *string
+	Tags        *[]string
+	Expanded    *bool
+	Position    *int
+}
+
+
+type apiStage struct {
+	Title     string
+	ItemCount int
+}
+
+
+type apiTag struct {
+	Expression string
+	ItemCount  int
+}
+
+
+type apiFilter struct {
+	Filter string
+}
+
+
+/* ================================================================================ Private variables */
+var apiServer *http.Server
+
+/* Clients have to send the token of the running session, so that other local programs and web pages cannot use the API unnoticed */
+var apiToken string
+
+
+/* ================================================================================ Public functions */
+func StartLocalAPI(address string) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+		return fmt.Errorf("The API is only served on localhost, not on \"%s\"", host)
+	}
+
+	StopLocalAPI()
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/board",  apiHandler(handleAPIBoard))
+	mux.HandleFunc("/api/stages", apiHandler(handleAPIStages))
+	mux.HandleFunc("/api/items",  apiHandler(handleAPIItems))
+	mux.HandleFunc("/api/items/", apiHandler(handleAPIItem))
+	mux.HandleFunc("/api/tags",   apiHandler(handleAPITags))
+	mux.HandleFunc("/api/filter", apiHandler(handleAPIFilter))
+
+	apiServer = &http.Server{ Handler: mux }
+	server   := apiServer
+
+	go func() {
+		if err := server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			fmt.Println(err)
+		}
+	}()
+
+	return nil
+}
+
+
+/* Returns the token of the session, which stays the same when the API is restarted */
+func LocalAPIToken() string {
+	if apiToken == "" {
+		apiToken = NewItemId() + NewItemId()
+	}
+	return apiToken
+}
+
+
+func StopLocalAPI() {
+	if apiServer != nil {
+		apiServer.Close()
+		apiServer = nil
+	}
+}
+
+
+/* ================================================================================ Private functions */
+/* Checks the request and runs the handler on the UI side, as handlers read and change the board just like the UI does */
+func apiHandler(handle http.HandlerFunc) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		/* Only accept localhost as host, so that web pages cannot reach the API by rebinding their domain to 127.0.0.1 */
+		host, _, err := net.SplitHostPort(request.Host)
+		if err != nil {
+			host = request.Host
+		}
+		if ip := net.ParseIP(host); host != "localhost" && (ip == nil || !ip.IsLoopback()) {
+			writeAPIError(writer, http.StatusForbidden, "Host \"%s\" not allowed", request.Host)
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(request.Header.Get(API_TOKEN_HEADER)), []byte(LocalAPIToken())) != 1 {
+			writeAPIError(writer, http.StatusUnauthorized, "Missing or wrong %s header", API_TOKEN_HEADER)
+			return
+		}
+
+		/* Requiring JSON also keeps web pages from sending requests as simple (e.g. form) requests */
+		if request.Method == http.MethodPost || request.Method == http.MethodPut || request.Method == http.MethodPatch {
+			if mediaType, _, _ := mime.ParseMediaType(request.Header.Get("Content-Type")); mediaType != "application/json" {
+				writeAPIError(writer, http.StatusUnsupportedMediaType, "Content-Type must be application/json")
+				return
+			}
+		}
+
+		/* The body is read here, so that slow clients cannot block the UI */
+		body, err := io.ReadAll(io.LimitReader(request.Body, API_MAX_BODY_SIZE))
+		if err != nil {
+			writeAPIError(writer, http.StatusBadRequest, "%s", err)
+			return
+		}
+		request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if !RunOnUIAndWait(func() { handle(writer, request) }, API_UI_WAIT_DURATION) {
+			writeAPIError(writer, http.StatusServiceUnavailable, "The window did not respond")
+		}
+	}
+}
+
+
+func newAPIItem(item *Item, stage *Stage) apiItem {
+	tags := make([]string, len(item.Tags))
+	for i, tag := range item.Tags {
+		tags[i] = tag.Expression
+	}
+
+	return apiItem{ item.Id, stage.Title, item.Title, item.Description, tags, item.Expanded }
+}
+
+
+func apiTagsFromStrings(expressions []string) []Tag {
+	return ParseTagEditString(strings.Join(expressions, ";"))
+}
+
+
+func writeAPIResponse(writer http.ResponseWriter, status int, body interface{}) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+
+	if body != nil {
+		if err := json.NewEncoder(writer).Encode(body); err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+
+func writeAPIError(writer http.ResponseWriter, status int, format string, arguments ...interface{}) {
+	writeAPIResponse(writer, status, map[string]string{ "Error": fmt.Sprintf(format, arguments...) })
+}
+
+
+func readAPIRequest(writer http.ResponseWriter, request *http.Request, body interface{}) bool {
+	if err := json.NewDecoder(request.Body).Decode(body); err != nil {
+		writeAPIError(writer, http.StatusBadRequest, "Invalid JSON body: %s", err)
+		return false
+	}
+	return true
+}
+
+
+func handleAPIBoard(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writeAPIError(writer, http.StatusMethodNotAllowed, "Method %s not allowed", request.Method)
+		return
+	}
+
+	data, err := board.Data()
+	if err != nil {
+		writeAPIError(writer, http.StatusInternalServerError, "%s", err)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.Write(data)
+}
+
+
+func handleAPIStages(writer http.ResponseWriter, request *http.Request) {
+	switch request.Method {
+		case http.MethodGet:
+			stages := []apiStage{}
+			for _, stage := range board.Stages {
+				stages = append(stages, apiStage{ stage.Title, len(stage.Items) })
+			}
+			writeAPIResponse(writer, http.StatusOK, stages)
+
+		case http.MethodPost:
+			var stage apiStage
+			if !readAPIRequest(writer, request, &stage) {
+				return
+			}
+			if stage.Title == "" {
+				writeAPIError(writer, http.StatusBadRequest, "Stage title missing")
+				return
+			}
+
+			board.RecordUndoStep("New Stage " + stage.Title)
+			board.AppendStage(stage.Title)
+			writeAPIResponse(writer, http.StatusCreated, apiStage{ stage.Title, 0 })
+
+		default:
+			writeAPIError(writer, http.StatusMethodNotAllowed, "Method %s not allowed", request.Method)
+	}
+}
+
+
+/* Lists items (optionally by ?stage= and ?filter= with tag edit string) or creates an item */
+func handleAPIItems(writer http.ResponseWriter, request *http.Request) {
+	switch request.Method {
+		case http.MethodGet:
+			stageTitle := request.URL.Query().Get("stage")
+			filterTags := ParseTagEditString(request.URL.Query().Get("filter"))
+			items      := []apiItem{}
+
+			for _, stage := range board.Stages {
+				if stageTitle != "" && stage.Title != stageTitle {
+					continue
+				}
+				for _, item := range stage.Items {
+					if item.MatchesFilterTags(filterTags) {
+						items = append(items, newAPIItem(item, stage))
+					}
+				}
+			}
+			writeAPIResponse(writer, http.StatusOK, items)
+
+		case http.MethodPost:
+			var change apiItemChange
+			if !readAPIRequest(writer, request, &change) {
+				return
+			}
+			if change.Stage == nil || change.Title == nil {
+				writeAPIError(writer, http.StatusBadRequest, "Stage or title missing")
+				return
+			}
+
+			stage := board.StageByTitle(*change.Stage)
+			if stage == nil {
+				writeAPIError(writer, http.StatusNotFound, "No stage with title \"%s\"", *change.Stage)
+				return
+			}
+
+			description := ""
+			if change.Description != nil {
+				description = *change.Description
+			}
+			tags := []Tag(nil)
+			if change.Tags != nil {
+				tags = apiTagsFromStrings(*change.Tags)
+			}
+
+			board.RecordUndoStep("New Item " + *change.Title)
+			item := stage.AppendItem(*change.Title, tags, description, DefaultItemStyle)
+			stage.Rules.ApplyEnter(item)
+			item.Refresh()
+			item.SetFilterTags(board.FilterTags)
+			writeAPIResponse(writer, http.StatusCreated, newAPIItem(item, stage))
+
+		default:
+			writeAPIError(writer, http.StatusMethodNotAllowed, "Method %s not allowed", request.Method)
+	}
+}
+
+
+/* Gets, changes (including moves by giving stage and/or position) or removes the item given by /api/items/<id> */
+func handleAPIItem(writer http.ResponseWriter, request *http.Request) {
+	id       := strings.TrimPrefix(request.URL.Path, "/api/items/")
+	item     := board.ItemById(id)
+	archived := board.archivedItemById(id)
+
+	/* Archived items can only be restored by changing them, as their IDs must not be used twice */
+	if item == nil && archived != nil {
+		if request.Method != http.MethodPatch {
+			writeAPIError(writer, http.StatusConflict, "Item \"%s\" is archived, patch it to restore it", id)
+			return
+		}
+		item = archived.Item
+	}
+
+	if item == nil {
+		writeAPIError(writer, http.StatusNotFound, "No item with ID \"%s\"", id)
+		return
+	}
+
+	switch request.Method {
+		case http.MethodGet:
+			writeAPIResponse(writer, http.StatusOK, newAPIItem(item, board.ItemStage(item)))
+
+		case http.MethodPatch:
+			var change apiItemChange
+			if !readAPIRequest(writer, request, &change) {
+				return
+			}
+
+			/* Everything is checked before the first change, so failing requests change nothing */
+			sourceStage := board.ItemStage(item)
+			targetStage := sourceStage
+			if change.Stage != nil {
+				targetStage = board.StageByTitle(*change.Stage)
+				if targetStage == nil {
+					writeAPIError(writer, http.StatusNotFound, "No stage with title \"%s\"", *change.Stage)
+					return
+				}
+			} else if sourceStage == nil {
+				targetStage = board.StageByTitle(archived.Stage)
+			}
+
+			/* The position refers to the target stage without the moved item */
+			var reference *Item
+			if change.Position != nil && targetStage != nil {
+				others := []*Item{}
+				for _, other := range targetStage.Items {
+					if other != item {
+						others = append(others, other)
+					}
+				}
+				if *change.Position >= 0 && *change.Position < len(others) {
+					reference = others[*change.Position]
+				}
+			}
+
+			move := sourceStage != nil && (change.Stage != nil || change.Position != nil)
+			if move {
+				if err := board.CheckItemMove(item, targetStage, reference); err != nil {
+					writeAPIError(writer, http.StatusConflict, "%s", err)
+					return
+				}
+			}
+
+			board.RecordUndoStep("Edit Item " + item.Title)
+			if sourceStage == nil {
+				board.restoreArchivedItem(archived, targetStage, reference)
+			} else if move {
+				board.moveItem(item, targetStage, reference, false)
+			}
+
+			if change.Title != nil {
+				item.Title = *change.Title
+			}
+			if change.Description != nil {
+				item.Description = *change.Description
+			}
+			if change.Tags != nil {
+				item.Tags = apiTagsFromStrings(*change.Tags)
+			}
+			if change.Expanded != nil {
+				item.Expanded = *change.Expanded
+			}
+			item.Refresh()
+
+			/* The item may have been restored or moved, and its tags may have changed its visibility */
+			board.RefreshBlockedItems()
+			board.ApplyTagFilter()
+
+			writeAPIResponse(writer, http.StatusOK, newAPIItem(item, board.ItemStage(item)))
+
+		case http.MethodDelete:
+			board.RemoveItem(item)
+			writeAPIResponse(writer, http.StatusNoContent, nil)
+
+		default:
+			writeAPIError(writer, http.StatusMethodNotAllowed, "Method %s not allowed", request.Method)
+	}
+}
+
+
+func handleAPITags(writer http.ResponseWriter, request *http.Request) {
+	if request.Method != http.MethodGet {
+		writeAPIError(writer, http.StatusMethodNotAllowed, "Method %s not allowed", request.Method)
+		return
+	}
+
+	tags := []apiTag{}
+	for expression, count := range board.TagCounts() {
+		tags = append(tags, apiTag{ expression, count })
+	}
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Expression < tags[j].Expression })
+
+	writeAPIResponse(writer, http.StatusOK, tags)
+}
+
+
+func handleAPIFilter(writer http.ResponseWriter, request *http.Request) {
+	switch request.Method {
+		case http.MethodGet:
+			writeAPIResponse(writer, http.StatusOK, apiFilter{ ComposeTagEditString(board.FilterTags) })
+
+		case http.MethodPut:
+			var filter apiFilter
+			if !readAPIRequest(writer, request, &filter) {
+				return
+			}
+
+			/* Set the filter through the entry binding, just like typing into the filter entry */
+			boardFilterChanged(filter.Filter)
+			writeAPIResponse(writer, http.StatusOK, filter)
+
+		default:
+			writeAPIError(writer, http.StatusMethodNotAllowed, "Method %s not allowed", request.Method)
+	}
+}
\ No newline at end of file
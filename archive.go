@@ -0,0 +1,301 @@
+package main
+
+/* This file contains the archive of completed items, which are kept in the board file but not rendered, and the dialogs to browse and configure it */
+
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"image/color"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"fyne.io/fyne/v2/theme"
+)
+
+
+/* ================================================================================ Public types */
+type ArchivedItem struct {
+	Item       *Item
+	Stage      string
+	ArchivedAt time.Time
+}
+
+
+/* Items are archived automatically after staying in the stage for the given number of days */
+type AutoArchiveRule struct {
+	Stage string
+	Days  int
+}
+
+
+/* ================================================================================ Public methods */
+func (w *Board) ArchiveItem(item *Item) bool {
+	stage := w.ItemStage(item)
+	if stage == nil {
+		return false
+	}
+
+	w.RecordUndoStep("Archive Item")
+	stage.RemoveItem(item)
+	w.Archive = append(w.Archive, &ArchivedItem{ item, stage.Title, time.Now() })
+	w.RefreshBlockedItems()
+
+	return true
+}
+
+
+/* Restores the item to the end of its original stage, which is recreated if it was removed in the meantime */
+func (w *Board) RestoreArchivedItem(archived *ArchivedItem) bool {
+	if w.archivedItemIndex(archived) < 0 {
+		return false
+	}
+
+	w.RecordUndoStep("Restore Item")
+	w.restoreArchivedItem(archived, nil, nil)
+
+	return true
+}
+
+
+func (w *Board) RemoveArchivedItem(archived *ArchivedItem) bool {
+	i := w.archivedItemIndex(archived)
+	if i < 0 {
+		return false
+	}
+
+	w.RecordUndoStep("Remove Archived Item")
+	w.Archive = append(w.Archive[:i], w.Archive[i+1:]...)
+	w.removeItemDependencies(archived.Item.Id)
+
+	return true
+}
+
+
+/* Archives the items exceeding the time in the stage given by the auto-archive rule and returns their count */
+func (w *Board) AutoArchiveItems() int {
+	if w.AutoArchive == nil || w.AutoArchive.Days < 1 {
+		return 0
+	}
+
+	stage := w.StageByTitle(w.AutoArchive.Stage)
+	if stage == nil {
+		return 0
+	}
+
+	deadline := time.Now().AddDate(0, 0, -w.AutoArchive.Days)
+	unmarked := []*Item{}
+	expired  := []*Item{}
+
+	for _, item := range stage.Items {
+		if item.StageEntered == nil {
+			unmarked = append(unmarked, item)
+		} else if item.StageEntered.Before(deadline) {
+			expired = append(expired, item)
+		}
+	}
+	if len(unmarked) < 1 && len(expired) < 1 {
+		return 0
+	}
+
+	w.RecordUndoStep("Auto-Archive Items")
+
+	/* Items of boards saved by older versions have no stage entry time, so their time in the stage starts now */
+	for _, item := range unmarked {
+		item.MarkStageEntered()
+	}
+	for _, item := range expired {
+		w.ArchiveItem(item)
+	}
+
+	return len(expired)
+}
+
+
+func (w *Board) ShowArchiveDialog() {
+	matches  := []*ArchivedItem{}
+	selected := -1
+
+	list := widget.NewList(
+		func() int {
+			return len(matches)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, object fyne.CanvasObject) {
+			archived := matches[id]
+			object.(*widget.Label).SetText(fmt.Sprintf("%s    (%s, archived %s)", archived.Item.Title, archived.Stage, archived.ArchivedAt.Local().Format("2006-01-02 15:04")))
+		},
+	)
+	list.OnSelected   = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(id widget.ListItemID) { selected = -1 }
+
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("Search title, description, tags or stage ...")
+	searchEntry.OnChanged = func(text string) {
+		matches  = w.searchArchive(text)
+		selected = -1
+		list.UnselectAll()
+		list.Refresh()
+	}
+	searchEntry.OnChanged("")
+
+	restoreButton := widget.NewButtonWithIcon("Restore", theme.ContentUndoIcon(),
+		func() {
+			if selected >= 0 && selected < len(matches) {
+				w.RestoreArchivedItem(matches[selected])
+				searchEntry.OnChanged(searchEntry.Text)
+			}
+		},
+	)
+	removeButton := widget.NewButtonWithIcon("Remove", theme.DeleteIcon(),
+		func() {
+			if selected < 0 || selected >= len(matches) {
+				return
+			}
+
+			archived := matches[selected]
+			ShowConfirmDialog("Remove Archived Item", "This will remove the item from the archive permanently.\n\nAre you sure?\n",
+				func() {
+					w.RemoveArchivedItem(archived)
+					searchEntry.OnChanged(searchEntry.Text)
+				},
+			)
+		},
+	)
+
+	listSizer := canvas.NewRectangle(color.RGBA{ 0, 0, 0, 0 })
+	listSizer.SetMinSize(fyne.NewSize(600, 300))
+
+	dialogContainer := container.NewBorder(searchEntry, container.NewGridWithColumns(2, restoreButton, removeButton), nil, nil, container.NewMax(listSizer, list))
+
+	dialog.ShowCustom(fmt.Sprintf("Archive (%d Items)", len(w.Archive)), "Close", dialogContainer, window)
+	window.Canvas().Focus(searchEntry)
+}
+
+
+func (w *Board) ShowAutoArchiveSettingsDialog() {
+	rule := w.AutoArchive
+	if rule == nil {
+		rule = &AutoArchiveRule{ "Done", 14 }
+	}
+
+	stageTitles := make([]string, len(w.Stages))
+	for i, stage := range w.Stages {
+		stageTitles[i] = stage.Title
+	}
+
+	enabledCheck := widget.NewCheck("", nil)
+	enabledCheck.SetChecked(w.AutoArchive != nil)
+	stageEntry := widget.NewSelectEntry(stageTitles)
+	stageEntry.SetText(rule.Stage)
+	daysEntry := widget.NewEntry()
+	daysEntry.SetText(strconv.Itoa(rule.Days))
+
+	form := widget.NewForm(
+		widget.NewFormItem("Enabled",    enabledCheck),
+		widget.NewFormItem("Stage",      stageEntry),
+		widget.NewFormItem("After Days", daysEntry),
+	)
+
+	dialog.ShowCustomConfirm("Auto-Archive Settings", "OK", "Cancel", form,
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			w.RecordUndoStep("Edit Auto-Archive Settings")
+
+			days, err := strconv.Atoi(strings.TrimSpace(daysEntry.Text))
+			if !enabledCheck.Checked || err != nil || days < 1 {
+				w.AutoArchive = nil
+				return
+			}
+
+			w.AutoArchive = &AutoArchiveRule{ stageEntry.Text, days }
+			w.AutoArchiveItems()
+		}, window,
+	)
+}
+
+
+/* ================================================================================ Private methods */
+/* Restores the item without recording an undo step, before the reference item in the stage (or its original stage if nil), entering the stage like a move if it is another one */
+func (w *Board) restoreArchivedItem(archived *ArchivedItem, stage *Stage, reference *Item) {
+	i := w.archivedItemIndex(archived)
+	if i < 0 {
+		return
+	}
+
+	if stage == nil {
+		stage = w.StageByTitle(archived.Stage)
+	}
+	if stage == nil {
+		w.AppendStage(archived.Stage)
+		stage = w.Stages[len(w.Stages) - 1]
+	}
+
+	w.Archive = append(w.Archive[:i], w.Archive[i+1:]...)
+
+	item := archived.Item
+	item.ExtendBaseWidget(item)
+	item.MarkStageEntered()
+	if stage.Title != archived.Stage {
+		stage.Rules.ApplyEnter(item)
+	}
+	stage.PlaceItem(item, reference, false)
+	item.SetFilterTags(w.FilterTags)
+	w.RefreshBlockedItems()
+}
+
+
+func (w *Board) archivedItemIndex(toFind *ArchivedItem) int {
+	for i, archived := range w.Archive {
+		if archived == toFind {
+			return i
+		}
+	}
+	return -1
+}
+
+
+func (w *Board) archivedItemById(id string) *ArchivedItem {
+	for _, archived := range w.Archive {
+		if archived.Item != nil && archived.Item.Id == id {
+			return archived
+		}
+	}
+	return nil
+}
+
+
+/* IDs have to stay unique across the board and its archive, e.g. for restoring items */
+func (w *Board) HasItemId(id string) bool {
+	return w.ItemById(id) != nil || w.archivedItemById(id) != nil
+}
+
+
+/* Returns the archived items containing the search text (case-insensitive), most recently archived first */
+func (w *Board) searchArchive(text string) []*ArchivedItem {
+	text    = strings.ToLower(strings.TrimSpace(text))
+	matches := []*ArchivedItem{}
+
+	for _, archived := range w.Archive {
+		searchable := strings.ToLower(strings.Join([]string{ archived.Item.Title, archived.Item.Description, ComposeTagEditString(archived.Item.Tags), archived.Stage }, "\n"))
+
+		if strings.Contains(searchable, text) {
+			matches = append(matches, archived)
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].ArchivedAt.After(matches[j].ArchivedAt) })
+
+	return matches
+}
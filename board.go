@@ -6,6 +6,8 @@ package main
 /* ================================================================================ Imports */
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/widget"
 	"fyne.io/fyne/v2/container"
@@ -15,11 +17,34 @@ import (
 
 /* ================================================================================ Public types */
 type Board struct {
-	widget.BaseWidget                          `json:"-"`
-	Name            string
-	Stages          []*Stage
-	FilterTags      []Tag                      `json:"-"`
-	OnFilterChanged func(tagEditString string) `json:"-"`
+	widget.BaseWidget                              `json:"-"`
+	Name                string
+	Stages              []*Stage
+	TodoTxt             *TodoTxtMapping            `json:",omitempty"`
+	ICalendarFile       string                     `json:",omitempty"`
+	TagVocabulary       []Tag                      `json:",omitempty"`
+	ItemTemplates       []ItemTemplate             `json:",omitempty"`
+	Archive             []*ArchivedItem            `json:",omitempty"`
+	AutoArchive         *AutoArchiveRule           `json:",omitempty"`
+	Dependencies        []Dependency               `json:",omitempty"`
+	BlockedWarningStage string                     `json:",omitempty"`
+	SavedFilters        []SavedFilter              `json:",omitempty"`
+	TagDefinitions      []TagDefinition            `json:",omitempty"`
+	FilterTags          []Tag                      `json:"-"`
+	OnFilterChanged     func(tagEditString string) `json:"-"`
+	OnChanged           func()                     `json:"-"`
+	selectionAnchor     *Item                      `json:"-"`
+	undoSteps           []undoStep                 `json:"-"`
+	redoSteps           []undoStep                 `json:"-"`
+	recordingUndoStep   bool                       `json:"-"`
+	modified            bool                       `json:"-"`
+}
+
+
+/* Named tag filters, to be applied e.g. via the command palette */
+type SavedFilter struct {
+	Name   string
+	Filter string
 }
 
 
@@ -41,7 +66,17 @@ func NewBoard(name string, filterChanged func(tagEditString string)) *Board {
 
 /* ================================================================================ Public methods */
 func (w *Board) Clear() {
-	w.Stages = w.Stages[:0]
+	w.Stages              = nil
+	w.TodoTxt             = nil
+	w.ICalendarFile       = ""
+	w.TagVocabulary       = nil
+	w.ItemTemplates       = nil
+	w.Archive             = nil
+	w.AutoArchive         = nil
+	w.Dependencies        = nil
+	w.BlockedWarningStage = ""
+	w.SavedFilters        = nil
+	w.TagDefinitions      = nil
 	w.Refresh()
 }
 
@@ -55,12 +90,46 @@ func (w *Board) Load(data []byte) error {
 	if err := json.Unmarshal(data, w); err != nil {
 		return err
 	}
+
+	/* Boards saved by older versions do not contain item IDs yet, derive them from the items, so that every load (e.g. of the versions to merge) gives the same IDs */
+	for _, stage := range w.Stages {
+		for i, item := range stage.Items {
+			if item.Id == "" {
+				item.Id = LegacyItemId(stage.Title, i, item.Title)
+			}
+		}
+	}
+
+	/* Damaged archive entries without item are dropped, so that no one has to check for them */
+	archive := []*ArchivedItem{}
+	for _, archived := range w.Archive {
+		if archived != nil && archived.Item != nil {
+			archive = append(archive, archived)
+		}
+	}
+	if len(archive) < len(w.Archive) {
+		w.Archive = archive
+	}
+
+	w.RefreshBlockedItems()
 	w.Refresh()
 
 	return nil
 }
 
 
+/* Boards are modified by every change recording an undo step, until they are marked as saved */
+func (w *Board) Modified() bool {
+	return w.modified
+}
+
+
+func (w *Board) MarkSaved() {
+	w.modified = false
+	w.notifyChanged()
+}
+
+
 func (w *Board) StageIndex(toFind *Stage) int {
 	for i, stage := range w.Stages {
 		if stage == toFind {
@@ -91,6 +160,54 @@ func (w *Board) ItemStage(toFind *Item) *Stage {
 }
 
 
+/* Returns the item on the board, archived items are not included (see HasItemId) */
+func (w *Board) ItemById(id string) *Item {
+	for _, stage := range w.Stages {
+		for _, item := range stage.Items {
+			if item.Id == id {
+				return item
+			}
+		}
+	}
+	return nil
+}
+
+
+/* Returns how many items use each tag expression */
+func (w *Board) TagCounts() map[string]int {
+	counts := map[string]int{}
+	for _, stage := range w.Stages {
+		for _, item := range stage.Items {
+			for _, tag := range item.Tags {
+				counts[tag.Expression]++
+			}
+		}
+	}
+	return counts
+}
+
+
+func (w *Board) SortedTagExpressions() []string {
+	expressions := []string{}
+	for expression := range w.TagCounts() {
+		expressions = append(expressions, expression)
+	}
+	sort.Strings(expressions)
+
+	return expressions
+}
+
+
+func (w *Board) StageByTitle(title string) *Stage {
+	for _, stage := range w.Stages {
+		if stage.Title == title {
+			return stage
+		}
+	}
+	return nil
+}
+
+
 func (w *Board) StageAtPosition(position fyne.Position) *Stage {
 	for _, stage := range w.Stages {
 		stageRect := Rectangle{ stage.Position(), stage.Size() }
@@ -103,10 +220,12 @@ func (w *Board) StageAtPosition(position fyne.Position) *Stage {
 }
 
 
+/* Appending and removing stages changes the last stage, which resolves blockers */
 func (w *Board) AppendStage(title string) {
 	stage := NewStage(title)
 
 	w.Stages = append(w.Stages, stage)
+	w.RefreshBlockedItems()
 	w.Refresh()
 }
 
@@ -117,16 +236,95 @@ func (w *Board) RemoveStage(toRemove *Stage) bool {
 		return false
 	}
 
+	w.RecordUndoStep("Remove Stage " + toRemove.Title)
 	w.Stages = append(w.Stages[:i], w.Stages[i+1:]...)
+	for _, item := range toRemove.Items {
+		w.removeItemDependencies(item.Id)
+	}
+	w.RefreshBlockedItems()
 	w.Refresh()
 
 	return true
 }
 
 
+/* All moves go through here (or MoveItemToBoard), so the leave and enter rules of the stages are evaluated */
+func (w *Board) MoveItem(item *Item, targetStage *Stage, reference *Item, after bool) error {
+	if err := w.CheckItemMove(item, targetStage, reference); err != nil {
+		return err
+	}
+
+	w.RecordUndoStep("Move Item")
+	w.moveItem(item, targetStage, reference, after)
+
+	return nil
+}
+
+
+/* Returns an error if the item cannot be moved there, e.g. as it lacks the tags required to leave its stage */
+func (w *Board) CheckItemMove(item *Item, targetStage *Stage, reference *Item) error {
+	sourceStage := w.ItemStage(item)
+	if sourceStage == nil || targetStage == nil || reference == item {
+		return fmt.Errorf("Item \"%s\" or its target is not on the board", item.Title)
+	}
+	if reference != nil && targetStage.ItemIndex(reference) < 0 {
+		return fmt.Errorf("Item \"%s\" is not in stage \"%s\"", reference.Title, targetStage.Title)
+	}
+
+	if sourceStage != targetStage {
+		return sourceStage.Rules.CheckLeave(item, sourceStage)
+	}
+	return nil
+}
+
+
+/* Moves the item to the end of the stage with the same title on the target board, or of its first stage */
+func (w *Board) MoveItemToBoard(item *Item, target *Board) error {
+	sourceStage := w.ItemStage(item)
+	if sourceStage == nil || target == nil || target == w {
+		return fmt.Errorf("Item \"%s\" is not on the board or already on the target board", item.Title)
+	}
+
+	if err := sourceStage.Rules.CheckLeave(item, sourceStage); err != nil {
+		return err
+	}
+
+	/* Both boards keep their own history */
+	w.RecordUndoStep("Move Item to Board " + target.Name)
+	target.RecordUndoStep("Move Item from Board " + w.Name)
+
+	targetStage := target.StageByTitle(sourceStage.Title)
+	if targetStage == nil {
+		if len(target.Stages) < 1 {
+			target.AppendStage(sourceStage.Title)
+		}
+		targetStage = target.Stages[0]
+	}
+
+	item.MarkStageEntered()
+	targetStage.Rules.ApplyEnter(item)
+	item.Refresh()
+
+	sourceStage.RemoveItem(item)
+	targetStage.PlaceItem(item, nil, true)
+	item.SetFilterTags(target.FilterTags)
+
+	/* Relations are stored per board, so they do not survive the move */
+	w.removeItemDependencies(item.Id)
+	item.SetBlocked(false)
+
+	return nil
+}
+
+
 func (w *Board) RemoveItem(toRemove *Item) {
+	if w.ItemStage(toRemove) != nil {
+		w.RecordUndoStep("Remove Item")
+	}
+
 	for _, stage := range w.Stages {
 		if stage.RemoveItem(toRemove) {
+			w.removeItemDependencies(toRemove.Id)
 			w.Refresh()
 			return
 		}
@@ -137,6 +335,7 @@ func (w *Board) RemoveItem(toRemove *Item) {
 func (w *Board) ShowCreateStageDialog() {
 	ShowEntryDialog("New Stage", "Title ...", "",
 		func(text string) {
+			w.RecordUndoStep("New Stage " + text)
 			w.AppendStage(text)
 		},
 	)
@@ -182,6 +381,22 @@ func (w *Board) ToggleFilterTag(tag Tag) {
 }
 
 
+/* ================================================================================ Private methods */
+/* Moves the checked item without recording an undo step */
+func (w *Board) moveItem(item *Item, targetStage *Stage, reference *Item, after bool) {
+	sourceStage := w.ItemStage(item)
+	if sourceStage != targetStage {
+		item.MarkStageEntered()
+		targetStage.Rules.ApplyEnter(item)
+		item.Refresh()
+	}
+
+	sourceStage.RemoveItem(item)
+	targetStage.PlaceItem(item, reference, after)
+	w.RefreshBlockedItems()
+}
+
+
 /* ================================================================================ Public rendering methods */
 func (w *Board) CreateRenderer() fyne.WidgetRenderer {
 	w.ExtendBaseWidget(w)
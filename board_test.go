@@ -0,0 +1,35 @@
+package main
+
+/* Tests of loading boards */
+
+
+/* ================================================================================ Imports */
+import (
+	"testing"
+	"fyne.io/fyne/v2/test"
+)
+
+
+/* ================================================================================ Tests */
+func TestLoadBoard(t *testing.T) {
+	test.NewApp()
+
+	board := NewBoard("", nil)
+	data  := `{"Name":"Test","Stages":[{"Title":"Todo","Items":[{"Title":"A"}]}],"Archive":[{"Item":null,"Stage":"Todo"},null,{"Item":{"Id":"b","Title":"B"},"Stage":"Done"}]}`
+	if err := board.Load([]byte(data)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(board.Archive) != 1 || board.Archive[0].Item.Id != "b" {
+		t.Errorf("archive entries without item were not dropped: %+v", board.Archive)
+	}
+	if item := board.Stages[0].Items[0]; item.Id != LegacyItemId("Todo", 0, "A") {
+		t.Errorf("item without ID got ID %q, want the legacy ID", item.Id)
+	}
+
+	/* The remaining code paths reading archived items do not panic */
+	if matches := board.searchArchive("b"); len(matches) != 1 {
+		t.Errorf("searchArchive() found %d items, want 1", len(matches))
+	}
+	board.RefactorTags([]Tag{ { "x" } }, Tag{ "y" })
+}
@@ -0,0 +1,139 @@
+package main
+
+/* BoardTab is a type describing a board opened in a tab of the main window, which holds the file, filter and saved state of the board and its tab label */
+
+
+/* ================================================================================ Imports */
+import (
+	"image/color"
+	"github.com/fsnotify/fsnotify"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+
+/* ================================================================================ Public types */
+type BoardTab struct {
+	Board              *Board
+	SaveFileURI        fyne.URI
+	FilterText         string
+	Label              *TappableCustomLabel
+	OnFileChanged      func(tab *BoardTab)
+	SavedFileData      []byte
+	FileChangeReported bool
+	fileWatcher        *fsnotify.Watcher
+	watchedFileURI     string
+}
+
+
+/* ================================================================================ Public variables */
+var ActiveBoardTabStyle   = PaintStyle{ color.RGBA{ 255, 255, 255, 255 }, color.RGBA{ 255, 255, 255, 31 }, color.RGBA{ 255, 255, 255, 127 }, 1 }
+var InactiveBoardTabStyle = PaintStyle{ color.RGBA{ 170, 170, 170, 255 }, color.RGBA{ 0, 0, 0, 0 }, color.RGBA{ 0, 0, 0, 0 }, 0 }
+
+
+/* ================================================================================ Public functions */
+func NewBoardTab(board *Board, selected func(tab *BoardTab), fileChanged func(tab *BoardTab)) *BoardTab {
+	tab := &BoardTab{ Board: board, OnFileChanged: fileChanged }
+
+	tab.Label = NewTappableCustomLabel(fyne.TextAlignCenter, InactiveBoardTabStyle, false, "", theme.TextSize(), fyne.TextStyle{}, Paddings{ 0.5, 0.5, 0.5, 0.5 }, Paddings{ 0.0, 0.0, 2.0, 2.0 },
+		func() {
+			selected(tab)
+		},
+	)
+	tab.SetSaved(nil)
+
+	return tab
+}
+
+
+/* ================================================================================ Public methods */
+func (t *BoardTab) SetSaveFileURI(uri fyne.URI) {
+	t.SaveFileURI = uri
+
+	if uri != nil && t.fileWatcher != nil && uri.String() == t.watchedFileURI {
+		return
+	}
+
+	t.stopWatching()
+	t.startWatching()
+}
+
+
+/* Remembers the file content and marks the board as saved, fileData is nil for boards without file */
+func (t *BoardTab) SetSaved(fileData []byte) {
+	t.SavedFileData = fileData
+	t.Board.MarkSaved()
+	t.SyncLabel()
+}
+
+
+func (t *BoardTab) Modified() bool {
+	return t.Board.Modified()
+}
+
+
+func (t *BoardTab) Title() string {
+	title := t.Board.Name
+	if title == "" {
+		title = "Unnamed Board"
+	}
+	if t.Modified() {
+		title += " *"
+	}
+	return title
+}
+
+
+/* Updates the tab label text and returns whether it changed (which may change its size) */
+func (t *BoardTab) SyncLabel() bool {
+	title := t.Title()
+	if t.Label.Text == title {
+		return false
+	}
+
+	t.Label.Text = title
+	t.Label.Refresh()
+
+	return true
+}
+
+
+func (t *BoardTab) SetActive(active bool) {
+	if active {
+		t.Label.Style = ActiveBoardTabStyle
+	} else {
+		t.Label.Style = InactiveBoardTabStyle
+	}
+	t.Label.Refresh()
+}
+
+
+func (t *BoardTab) Close() {
+	t.stopWatching()
+}
+
+
+/* ================================================================================ Private methods */
+func (t *BoardTab) startWatching() {
+	if t.SaveFileURI == nil {
+		return
+	}
+
+	t.watchedFileURI = t.SaveFileURI.String()
+	t.fileWatcher    = WatchBoardFile(t.SaveFileURI,
+		func() {
+			if t.OnFileChanged != nil {
+				t.OnFileChanged(t)
+			}
+		},
+	)
+}
+
+
+func (t *BoardTab) stopWatching() {
+	if t.fileWatcher != nil {
+		t.fileWatcher.Close()
+		t.fileWatcher    = nil
+		t.watchedFileURI = ""
+	}
+}
@@ -0,0 +1,429 @@
+package main
+
+/* This file contains the command-line interface to manipulate board files without opening a window */
+
+
+/* ================================================================================ Imports */
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"fyne.io/fyne/v2"
+)
+
+
+/* ================================================================================ Private types */
+type commandLineCommand struct {
+	Usage       string
+	Description string
+	Run         func(arguments []string, defaultBoardPath string) error
+}
+
+
+/* ================================================================================ Private variables */
+var commandLineCommands map[string]commandLineCommand
+
+/* Returned after the flag package already printed the usage of a command */
+var errCommandLineUsage = errors.New("usage")
+
+var boardExportFunctions = map[string]func(board *Board) ([]byte, error){
+	"json": func(board *Board) ([]byte, error) { return board.Data() },
+	"html": ExportBoardHTML,
+	"png":  ExportBoardPNG,
+	"pdf":  ExportBoardPDF,
+	"ics":  ExportBoardICalendar,
+}
+
+
+/* ================================================================================ Public functions */
+/* Runs the command given by the arguments (without program name) and returns the exit code */
+func RunCommandLine(arguments []string, defaultBoardURI fyne.URI) int {
+	defaultBoardPath := ""
+	if defaultBoardURI != nil && defaultBoardURI.Scheme() == "file" {
+		defaultBoardPath = defaultBoardURI.Path()
+	}
+
+	command, found := commandLineCommands[arguments[0]]
+	if !found {
+		fmt.Fprintf(os.Stderr, "Unknown command \"%s\"\n\n", arguments[0])
+		printCommandLineUsage(os.Stderr)
+		return 2
+	}
+
+	if err := command.Run(arguments[1:], defaultBoardPath); err == errCommandLineUsage {
+		return 2
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	return 0
+}
+
+
+/* Returns whether the arguments (without program name) start with a command, as others are meant for the window, e.g. board files given by file managers */
+func IsCommandLineCommand(arguments []string) bool {
+	if len(arguments) < 1 {
+		return false
+	}
+
+	_, found := commandLineCommands[arguments[0]]
+	return found
+}
+
+
+/* ================================================================================ Private functions */
+func init() {
+	/* Initialized here, because the help command refers to the map itself */
+	commandLineCommands = map[string]commandLineCommand{
+		"help":   { "help", "Show this help", runHelpCommand },
+		"list":   { "list [--board FILE] [--stage STAGE] [--filter TAGS]", "List items as tab-separated ID, stage, title and tags", runListCommand },
+		"add":    { "add [--board FILE] --stage STAGE --title TITLE [--tags TAGS] [--description TEXT]", "Add an item to the end of a stage and print its ID", runAddCommand },
+		"edit":   { "edit [--board FILE] --item ITEM [--title TITLE] [--tags TAGS] [--description TEXT]", "Edit an item given by ID or title", runEditCommand },
+		"move":   { "move [--board FILE] --item ITEM --stage STAGE [--position INDEX]", "Move an item given by ID or title to a stage (to the end by default)", runMoveCommand },
+		"remove": { "remove [--board FILE] --item ITEM", "Remove an item given by ID or title", runRemoveCommand },
+		"merge":  { "merge [--output FILE] BASE OURS THEIRS", "Three-way merge of board files, usable as git merge driver (\"bankan merge %O %A %B\"), writes to OURS by default and fails on conflicts", runMergeCommand },
+		"export": { "export [--board FILE] --format json|html|png|pdf|ics|todotxt [--output FILE]", "Export the board (to standard output by default)", runExportCommand },
+	}
+}
+
+
+func printCommandLineUsage(writer io.Writer) {
+	fmt.Fprintf(writer, "Usage:\n  bankan                  Open the board window\n")
+
+	for _, name := range []string{ "list", "add", "edit", "move", "remove", "export", "merge", "help" } {
+		command := commandLineCommands[name]
+		fmt.Fprintf(writer, "  bankan %s\n      %s\n", command.Usage, command.Description)
+	}
+
+	fmt.Fprintf(writer, "\nFILE defaults to the board of the tab active last in the window, TAGS use the item dialog syntax (\"Tag1=Value1; Tag2; ...\").\n")
+}
+
+
+func newCommandFlagSet(name string, defaultBoardPath string) (*flag.FlagSet, *string) {
+	flagSet := flag.NewFlagSet(name, flag.ContinueOnError)
+	flagSet.Usage = func() { fmt.Fprintf(flagSet.Output(), "Usage: bankan %s\n", commandLineCommands[name].Usage) }
+
+	return flagSet, flagSet.String("board", defaultBoardPath, "board file")
+}
+
+
+func loadBoardFile(path string) (*Board, error) {
+	if path == "" {
+		return nil, fmt.Errorf("No board file given")
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	board := NewBoard("", nil)
+	if err := board.Load(data); err != nil {
+		return nil, err
+	}
+
+	return board, nil
+}
+
+
+func saveBoardFile(board *Board, path string) error {
+	data, err := board.Data()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+
+	exportBoardICalendarFile(board)
+
+	return nil
+}
+
+
+func findCommandLineItem(board *Board, idOrTitle string) (*Item, error) {
+	if item := board.ItemById(idOrTitle); item != nil {
+		return item, nil
+	}
+	if item := board.itemByTitle(idOrTitle); item != nil {
+		return item, nil
+	}
+	return nil, fmt.Errorf("No item with ID or title \"%s\"", idOrTitle)
+}
+
+
+func findCommandLineStage(board *Board, title string) (*Stage, error) {
+	if stage := board.StageByTitle(title); stage != nil {
+		return stage, nil
+	}
+	return nil, fmt.Errorf("No stage with title \"%s\"", title)
+}
+
+
+func runHelpCommand(arguments []string, defaultBoardPath string) error {
+	printCommandLineUsage(os.Stdout)
+	return nil
+}
+
+
+func runListCommand(arguments []string, defaultBoardPath string) error {
+	flagSet, boardPath := newCommandFlagSet("list", defaultBoardPath)
+	stageTitle         := flagSet.String("stage", "", "only list items of this stage")
+	filter             := flagSet.String("filter", "", "only list items with any of these tags")
+	if flagSet.Parse(arguments) != nil {
+		return errCommandLineUsage
+	}
+
+	board, err := loadBoardFile(*boardPath)
+	if err != nil {
+		return err
+	}
+
+	filterTags := ParseTagEditString(*filter)
+
+	for _, stage := range board.Stages {
+		if *stageTitle != "" && stage.Title != *stageTitle {
+			continue
+		}
+
+		for _, item := range stage.Items {
+			if item.MatchesFilterTags(filterTags) {
+				fmt.Printf("%s\t%s\t%s\t%s\n", item.Id, stage.Title, item.Title, strings.TrimSpace(ComposeTagEditString(item.Tags)))
+			}
+		}
+	}
+
+	return nil
+}
+
+
+func runAddCommand(arguments []string, defaultBoardPath string) error {
+	flagSet, boardPath := newCommandFlagSet("add", defaultBoardPath)
+	stageTitle         := flagSet.String("stage", "", "stage to add the item to")
+	title              := flagSet.String("title", "", "item title")
+	tags               := flagSet.String("tags", "", "item tags")
+	description        := flagSet.String("description", "", "item description")
+	if flagSet.Parse(arguments) != nil {
+		return errCommandLineUsage
+	}
+	if strings.TrimSpace(*title) == "" {
+		return fmt.Errorf("No item title given")
+	}
+
+	board, err := loadBoardFile(*boardPath)
+	if err != nil {
+		return err
+	}
+
+	stage, err := findCommandLineStage(board, *stageTitle)
+	if err != nil {
+		return err
+	}
+
+	item := stage.AppendItem(*title, ParseTagEditString(*tags), *description, DefaultItemStyle)
+
+	if err := saveBoardFile(board, *boardPath); err != nil {
+		return err
+	}
+
+	fmt.Println(item.Id)
+
+	return nil
+}
+
+
+func runEditCommand(arguments []string, defaultBoardPath string) error {
+	flagSet, boardPath := newCommandFlagSet("edit", defaultBoardPath)
+	itemName           := flagSet.String("item", "", "ID or title of the item")
+	title              := flagSet.String("title", "", "new item title")
+	tags               := flagSet.String("tags", "", "new item tags")
+	description        := flagSet.String("description", "", "new item description")
+	if flagSet.Parse(arguments) != nil {
+		return errCommandLineUsage
+	}
+
+	board, err := loadBoardFile(*boardPath)
+	if err != nil {
+		return err
+	}
+
+	item, err := findCommandLineItem(board, *itemName)
+	if err != nil {
+		return err
+	}
+
+	/* Only change the fields given on the command line, even if they are given empty */
+	flagSet.Visit(func(f *flag.Flag) {
+		switch f.Name {
+			case "title":
+				item.Title = *title
+			case "tags":
+				item.Tags = ParseTagEditString(*tags)
+			case "description":
+				item.Description = *description
+		}
+	})
+
+	return saveBoardFile(board, *boardPath)
+}
+
+
+func runMoveCommand(arguments []string, defaultBoardPath string) error {
+	flagSet, boardPath := newCommandFlagSet("move", defaultBoardPath)
+	itemName           := flagSet.String("item", "", "ID or title of the item")
+	stageTitle         := flagSet.String("stage", "", "stage to move the item to")
+	position           := flagSet.Int("position", -1, "zero-based position inside the stage, the end if negative")
+	if flagSet.Parse(arguments) != nil {
+		return errCommandLineUsage
+	}
+
+	board, err := loadBoardFile(*boardPath)
+	if err != nil {
+		return err
+	}
+
+	item, err := findCommandLineItem(board, *itemName)
+	if err != nil {
+		return err
+	}
+
+	stage, err := findCommandLineStage(board, *stageTitle)
+	if err != nil {
+		return err
+	}
+
+	/* Determine the reference before moving, so the position refers to the stage without the item */
+	var reference *Item
+	others := []*Item{}
+	for _, other := range stage.Items {
+		if other != item {
+			others = append(others, other)
+		}
+	}
+	if *position >= 0 && *position < len(others) {
+		reference = others[*position]
+	}
+
+	if err := board.MoveItem(item, stage, reference, false); err != nil {
+		return err
+	}
+
+	return saveBoardFile(board, *boardPath)
+}
+
+
+func runRemoveCommand(arguments []string, defaultBoardPath string) error {
+	flagSet, boardPath := newCommandFlagSet("remove", defaultBoardPath)
+	itemName           := flagSet.String("item", "", "ID or title of the item")
+	if flagSet.Parse(arguments) != nil {
+		return errCommandLineUsage
+	}
+
+	board, err := loadBoardFile(*boardPath)
+	if err != nil {
+		return err
+	}
+
+	item, err := findCommandLineItem(board, *itemName)
+	if err != nil {
+		return err
+	}
+
+	board.RemoveItem(item)
+
+	return saveBoardFile(board, *boardPath)
+}
+
+
+func runExportCommand(arguments []string, defaultBoardPath string) error {
+	flagSet, boardPath := newCommandFlagSet("export", defaultBoardPath)
+	format             := flagSet.String("format", "json", "export format")
+	output             := flagSet.String("output", "", "output file, standard output if empty")
+	if flagSet.Parse(arguments) != nil {
+		return errCommandLineUsage
+	}
+
+	board, err := loadBoardFile(*boardPath)
+	if err != nil {
+		return err
+	}
+
+	export, found := boardExportFunctions[*format]
+	if *format == "todotxt" {
+		mapping := board.TodoTxt
+		if mapping == nil {
+			mapping = NewTodoTxtMapping()
+		}
+		export, found = func(board *Board) ([]byte, error) { return ExportTodoTxt(board, mapping), nil }, true
+	}
+	if !found {
+		return fmt.Errorf("Unknown export format \"%s\"", *format)
+	}
+
+	data, err := export(board)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+
+	return os.WriteFile(*output, data, 0644)
+}
+
+
+func runMergeCommand(arguments []string, defaultBoardPath string) error {
+	flagSet, _ := newCommandFlagSet("merge", "")
+	output     := flagSet.String("output", "", "output file, OURS if empty")
+	if flagSet.Parse(arguments) != nil || flagSet.NArg() != 3 {
+		flagSet.Usage()
+		return errCommandLineUsage
+	}
+
+	boards := make([]*Board, 3)
+	for i, path := range flagSet.Args() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		/* The base is empty if the file was added on both sides */
+		boards[i] = NewBoard("", nil)
+		if len(bytes.TrimSpace(data)) > 0 {
+			if err := boards[i].Load(data); err != nil {
+				return fmt.Errorf("%s: %s", path, err)
+			}
+		}
+	}
+
+	merged, conflicts, err := MergeBoards(boards[0], boards[1], boards[2])
+	if err != nil {
+		return err
+	}
+
+	data, err := merged.Data()
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		*output = flagSet.Arg(1)
+	}
+	if err := os.WriteFile(*output, data, 0644); err != nil {
+		return err
+	}
+
+	if conflicts > 0 {
+		return fmt.Errorf("%d conflict(s), see stage \"%s\"", conflicts, CONFLICTS_STAGE_TITLE)
+	}
+
+	return nil
+}
\ No newline at end of file
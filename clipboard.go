@@ -0,0 +1,253 @@
+package main
+
+/* This file contains copy, cut and paste of items via the system clipboard, as JSON to round-trip between boards and instances, or as plain text (e.g. meeting notes) creating an item per line */
+
+
+/* ================================================================================ Imports */
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+
+/* ================================================================================ Constants */
+const (
+	CLIPBOARD_FORMAT = "bankan-items"
+)
+
+
+/* ================================================================================ Private types */
+type clipboardItems struct {
+	Format       string
+	Items        []*Item
+	Dependencies []Dependency `json:",omitempty"`
+}
+
+
+/* ================================================================================ Private variables */
+var markdownListItemPattern = regexp.MustCompile(`^\s*(?:[-*+]|\d+[.)])\s+(?:\[[ xX]\]\s+)?`)
+var markdownHeadingPattern  = regexp.MustCompile(`^\s*#{1,6}\s`)
+var hashTagPattern          = regexp.MustCompile(`(?:^|\s)#(\pL[^\s#;]*)`)
+var keyValueTagPattern      = regexp.MustCompile(`(?:^|\s)([^\s=;#]+=[^;]*;|[^\s=;#]+=[^\s;]+$)`)
+
+
+/* ================================================================================ Public functions */
+/* Composes the items with the dependencies between them */
+func ComposeClipboardText(items []*Item, dependencies []Dependency) (string, error) {
+	data, err := json.MarshalIndent(clipboardItems{ CLIPBOARD_FORMAT, items, dependencies }, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+
+/* Parses items copied by BanKan with the dependencies between them, or creates items from any other text */
+func ParseClipboardText(text string) ([]*Item, []Dependency) {
+	parsed := clipboardItems{}
+	if err := json.Unmarshal([]byte(text), &parsed); err == nil && parsed.Format == CLIPBOARD_FORMAT {
+		items := []*Item{}
+		for _, item := range parsed.Items {
+			if item != nil {
+				item.ExtendBaseWidget(item)
+				items = append(items, item)
+			}
+		}
+		return items, parsed.Dependencies
+	}
+
+	return ParseTextItems(text), nil
+}
+
+
+/* Creates an item per line, or per Markdown list item if there are any (other lines are added to the description of the preceding item), with inline "#tag" and "key=value;" segments as tags (hash tags start with a letter, so e.g. "#123" stays in the title), headings are added to the description of the following item */
+func ParseTextItems(text string) []*Item {
+	lines    := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	listMode := false
+	for _, line := range lines {
+		if markdownListItemPattern.MatchString(line) {
+			listMode = true
+			break
+		}
+	}
+
+	items   := []*Item{}
+	pending := []string{}
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		/* Headings and the text following them (or preceding the first list item) belong to the next item */
+		if markdownHeadingPattern.MatchString(line) || (listMode && !markdownListItemPattern.MatchString(line) && (len(pending) > 0 || len(items) < 1)) {
+			pending = append(pending, strings.TrimSpace(line))
+			continue
+		}
+
+		if listMode && !markdownListItemPattern.MatchString(line) {
+			previous            := items[len(items) - 1]
+			previous.Description = strings.TrimLeft(previous.Description + "\n" + strings.TrimSpace(line), "\n")
+			continue
+		}
+
+		title, tags := parseTextItemLine(markdownListItemPattern.ReplaceAllString(line, ""))
+		if title == "" {
+			/* Lines consisting of tags only tag the preceding item */
+			if len(items) > 0 {
+				items[len(items) - 1].Tags = append(items[len(items) - 1].Tags, tags...)
+			}
+			continue
+		}
+
+		items   = append(items, NewItem(title, tags, strings.Join(pending, "\n"), DefaultItemStyle))
+		pending = nil
+	}
+
+	/* Trailing headings have no item to describe, so they become one */
+	if len(pending) > 0 {
+		title := strings.TrimSpace(strings.TrimLeft(pending[0], "#"))
+		items  = append(items, NewItem(title, nil, strings.Join(pending[1:], "\n"), DefaultItemStyle))
+	}
+	return items
+}
+
+
+/* ================================================================================ Public methods */
+/* Returns the selected items, or the focused one if none is selected */
+func (w *Board) ClipboardSourceItems() []*Item {
+	if items := w.SelectedItems(); len(items) > 0 {
+		return items
+	}
+	if item := w.FocusedItem(); item != nil {
+		return []*Item{ item }
+	}
+	return nil
+}
+
+
+func (w *Board) CopyItems(items []*Item) bool {
+	if len(items) < 1 {
+		return false
+	}
+
+	copied := map[string]bool{}
+	for _, item := range items {
+		copied[item.Id] = true
+	}
+	dependencies := []Dependency{}
+	for _, dependency := range w.Dependencies {
+		if copied[dependency.Blocker] && copied[dependency.Blocked] {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	text, err := ComposeClipboardText(items, dependencies)
+	if err != nil {
+		fmt.Println(err)
+		return false
+	}
+
+	window.Clipboard().SetContent(text)
+	return true
+}
+
+
+func (w *Board) CutItems(items []*Item) {
+	if !w.CopyItems(items) {
+		return
+	}
+
+	w.RecordUndoStep(fmt.Sprintf("Cut %d Items", len(items)))
+	for _, item := range items {
+		w.RemoveItem(item)
+	}
+}
+
+
+/* Inserts the clipboard items after the reference item (or at the end of the stage if nil), with new IDs for items already on the board (and the dependencies between them following the IDs) */
+func (w *Board) PasteItems(stage *Stage, reference *Item) int {
+	items, dependencies := ParseClipboardText(window.Clipboard().Content())
+	if stage == nil || len(items) < 1 {
+		return 0
+	}
+
+	w.RecordUndoStep(fmt.Sprintf("Paste %d Items", len(items)))
+
+	ids := map[string]string{}
+	for _, item := range items {
+		previousId := item.Id
+		if item.Id == "" || w.HasItemId(item.Id) {
+			item.Id = NewItemId()
+		}
+		if previousId != "" {
+			ids[previousId] = item.Id
+		}
+		item.MarkStageEntered()
+		stage.Rules.ApplyEnter(item)
+
+		stage.PlaceItem(item, reference, true)
+		item.SetFilterTags(w.FilterTags)
+		reference = item
+	}
+
+	for _, dependency := range dependencies {
+		blocker, blockerFound := ids[dependency.Blocker]
+		blocked, blockedFound := ids[dependency.Blocked]
+		if blockerFound && blockedFound && blocker != blocked {
+			w.Dependencies = append(w.Dependencies, Dependency{ blocker, blocked })
+		}
+	}
+	w.RefreshBlockedItems()
+
+	return len(items)
+}
+
+
+/* Pastes after the focused item, or at the end of the first stage if none is focused */
+func (w *Board) PasteItemsAtFocus() {
+	if item := w.FocusedItem(); item != nil {
+		w.PasteItems(w.ItemStage(item), item)
+	} else if len(w.Stages) > 0 {
+		w.PasteItems(w.Stages[0], nil)
+	}
+}
+
+
+func (w *Item) CopyItem() {
+	board.CopyItems([]*Item{ w })
+}
+
+
+func (w *Item) CutItem() {
+	board.CutItems([]*Item{ w })
+}
+
+
+func (w *Item) PasteItemsAfter() {
+	board.PasteItems(board.ItemStage(w), w)
+}
+
+
+func (w *Stage) PasteItems() {
+	board.PasteItems(w, nil)
+}
+
+
+/* ================================================================================ Private functions */
+func parseTextItemLine(line string) (string, []Tag) {
+	segments := []string{}
+	collect  := func(match string) string {
+		segments = append(segments, strings.TrimPrefix(strings.TrimSpace(match), "#"))
+		return " "
+	}
+
+	line = hashTagPattern.ReplaceAllStringFunc(line, collect)
+	line = keyValueTagPattern.ReplaceAllStringFunc(line, collect)
+
+	title := strings.Trim(strings.Join(strings.Fields(line), " "), " ;")
+
+	return title, ParseTagEditString(strings.Join(segments, ";"))
+}
@@ -0,0 +1,315 @@
+package main
+
+/* This file contains the "blocks / blocked by" relations between items, referencing them by their ID, and the dialogs to edit them */
+
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"strings"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"fyne.io/fyne/v2/theme"
+)
+
+
+/* ================================================================================ Public types */
+type Dependency struct {
+	Blocker string
+	Blocked string
+}
+
+
+/* ================================================================================ Public methods */
+func (w *Board) AddDependency(blocker, blocked *Item) error {
+	if blocker == blocked {
+		return fmt.Errorf("An item cannot block itself")
+	}
+	if w.dependencyIndex(blocker.Id, blocked.Id) >= 0 {
+		return fmt.Errorf("Item \"%s\" already blocks \"%s\"", blocker.Title, blocked.Title)
+	}
+	if w.dependsOn(blocker.Id, blocked.Id) {
+		return fmt.Errorf("Item \"%s\" (indirectly) depends on \"%s\" already, which would create a cycle", blocker.Title, blocked.Title)
+	}
+
+	w.RecordUndoStep("Add Dependency")
+	w.Dependencies = append(w.Dependencies, Dependency{ blocker.Id, blocked.Id })
+	w.RefreshBlockedItems()
+
+	return nil
+}
+
+
+func (w *Board) RemoveDependency(blockerId, blockedId string) {
+	if i := w.dependencyIndex(blockerId, blockedId); i >= 0 {
+		w.RecordUndoStep("Remove Dependency")
+		w.Dependencies = append(w.Dependencies[:i], w.Dependencies[i+1:]...)
+		w.RefreshBlockedItems()
+	}
+}
+
+
+func (w *Board) BlockerIds(item *Item) []string {
+	ids := []string{}
+	for _, dependency := range w.Dependencies {
+		if dependency.Blocked == item.Id {
+			ids = append(ids, dependency.Blocker)
+		}
+	}
+	return ids
+}
+
+
+func (w *Board) BlockedIds(item *Item) []string {
+	ids := []string{}
+	for _, dependency := range w.Dependencies {
+		if dependency.Blocker == item.Id {
+			ids = append(ids, dependency.Blocked)
+		}
+	}
+	return ids
+}
+
+
+/* Blockers are resolved once they reach the last stage (the rightmost one, e.g. "Done"), are archived or removed */
+func (w *Board) IsBlockerResolved(id string) bool {
+	item := w.ItemById(id)
+	if item == nil {
+		return true
+	}
+
+	return w.ItemStageIndex(item) == len(w.Stages) - 1
+}
+
+
+func (w *Board) IsItemBlocked(item *Item) bool {
+	for _, id := range w.BlockerIds(item) {
+		if !w.IsBlockerResolved(id) {
+			return true
+		}
+	}
+	return false
+}
+
+
+/* Updates the blocked badges of all items, as moves, removals and relation changes can affect other items */
+func (w *Board) RefreshBlockedItems() {
+	for _, stage := range w.Stages {
+		for _, item := range stage.Items {
+			item.SetBlocked(w.IsItemBlocked(item))
+		}
+	}
+}
+
+
+/* Returns a warning if the item is blocked and would be moved into or beyond the blocked warning stage, otherwise an empty string */
+func (w *Board) BlockedMoveWarning(item *Item, targetStage *Stage) string {
+	if w.BlockedWarningStage == "" || !w.IsItemBlocked(item) {
+		return ""
+	}
+
+	warningStage := w.StageByTitle(w.BlockedWarningStage)
+	if warningStage == nil || w.StageIndex(targetStage) < w.StageIndex(warningStage) || w.ItemStage(item) == targetStage {
+		return ""
+	}
+
+	return fmt.Sprintf("Item \"%s\" is still blocked by unresolved items and should not be moved beyond stage \"%s\".\n\n%s", item.Title, w.BlockedWarningStage, strings.Join(w.DependencyChain(item), "\n"))
+}
+
+
+/* Describes the (transitive) blockers of the item as indented lines */
+func (w *Board) DependencyChain(item *Item) []string {
+	lines := []string{}
+	w.appendDependencyChain(&lines, item.Id, 0, map[string]bool{})
+
+	return lines
+}
+
+
+func (w *Board) ShowBlockedWarningStageDialog() {
+	stageTitles := make([]string, len(w.Stages))
+	for i, stage := range w.Stages {
+		stageTitles[i] = stage.Title
+	}
+
+	stageEntry := widget.NewSelectEntry(stageTitles)
+	stageEntry.SetPlaceHolder("Stage, empty to disable ...")
+	stageEntry.SetText(w.BlockedWarningStage)
+
+	dialog.ShowCustomConfirm("Warn When Moving Blocked Items Into", "OK", "Cancel", stageEntry,
+		func(confirmed bool) {
+			if confirmed {
+				w.RecordUndoStep("Edit Blocked Item Warning")
+				w.BlockedWarningStage = strings.TrimSpace(stageEntry.Text)
+			}
+		}, window,
+	)
+}
+
+
+func (w *Item) ShowDependenciesDialog() {
+	otherItems  := []*Item{}
+	otherTitles := []string{}
+	for _, stage := range board.Stages {
+		for _, item := range stage.Items {
+			if item != w {
+				otherItems  = append(otherItems, item)
+				otherTitles = append(otherTitles, fmt.Sprintf("%s (%s)", item.Title, stage.Title))
+			}
+		}
+	}
+
+	relationsBox := container.NewVBox()
+	chainLabel   := widget.NewLabel("")
+
+	var syncRelations func()
+	syncRelations = func() {
+		relationsBox.Objects = nil
+
+		addRelations := func(heading string, ids []string, remove func(id string)) {
+			relationsBox.Add(widget.NewLabelWithStyle(heading, fyne.TextAlignLeading, fyne.TextStyle{ Bold: true }))
+			if len(ids) < 1 {
+				relationsBox.Add(widget.NewLabel("-"))
+			}
+			for _, id := range ids {
+				relatedId := id
+				relationsBox.Add(container.NewBorder(nil, nil, nil,
+					widget.NewButtonWithIcon("", theme.DeleteIcon(), func() { remove(relatedId); syncRelations() }),
+					widget.NewLabel(board.dependencyItemTitle(relatedId)),
+				))
+			}
+		}
+
+		addRelations("Blocked by", board.BlockerIds(w), func(id string) { board.RemoveDependency(id, w.Id) })
+		addRelations("Blocks",     board.BlockedIds(w), func(id string) { board.RemoveDependency(w.Id, id) })
+		relationsBox.Refresh()
+
+		chain := board.DependencyChain(w)
+		if len(chain) < 1 {
+			chain = []string{ "-" }
+		}
+		chainLabel.SetText(strings.Join(chain, "\n"))
+	}
+	syncRelations()
+
+	otherSelect := widget.NewSelect(otherTitles, nil)
+	otherSelect.PlaceHolder = "Item ..."
+
+	addRelation := func(blocks bool) {
+		i := otherSelect.SelectedIndex()
+		if i < 0 {
+			return
+		}
+
+		var err error
+		if blocks {
+			err = board.AddDependency(w, otherItems[i])
+		} else {
+			err = board.AddDependency(otherItems[i], w)
+		}
+		if err != nil {
+			ShowReportDialog("Add Dependency", err.Error(), nil)
+			return
+		}
+
+		otherSelect.ClearSelected()
+		syncRelations()
+	}
+
+	addContainer := container.NewBorder(nil, nil, nil,
+		container.NewHBox(
+			widget.NewButton("Is Blocked by", func() { addRelation(false) }),
+			widget.NewButton("Blocks",        func() { addRelation(true) }),
+		),
+		otherSelect,
+	)
+
+	dialogContainer := container.NewVBox(relationsBox, widget.NewSeparator(), addContainer, widget.NewSeparator(), widget.NewLabelWithStyle("Dependency Chain", fyne.TextAlignLeading, fyne.TextStyle{ Bold: true }), chainLabel)
+
+	dialog.ShowCustom("Dependencies of \"" + w.Title + "\"", "Close", dialogContainer, window)
+}
+
+
+/* ================================================================================ Private methods */
+func (w *Board) dependencyIndex(blockerId, blockedId string) int {
+	for i, dependency := range w.Dependencies {
+		if dependency.Blocker == blockerId && dependency.Blocked == blockedId {
+			return i
+		}
+	}
+	return -1
+}
+
+
+/* Returns whether the item depends (directly or transitively) on the other item */
+func (w *Board) dependsOn(id, otherId string) bool {
+	visited := map[string]bool{}
+	pending := []string{ id }
+
+	for len(pending) > 0 {
+		current := pending[len(pending) - 1]
+		pending  = pending[:len(pending) - 1]
+
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		for _, dependency := range w.Dependencies {
+			if dependency.Blocked != current {
+				continue
+			}
+			if dependency.Blocker == otherId {
+				return true
+			}
+			pending = append(pending, dependency.Blocker)
+		}
+	}
+	return false
+}
+
+
+func (w *Board) removeItemDependencies(id string) {
+	dependencies := []Dependency{}
+	for _, dependency := range w.Dependencies {
+		if dependency.Blocker != id && dependency.Blocked != id {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	w.Dependencies = dependencies
+	w.RefreshBlockedItems()
+}
+
+
+func (w *Board) dependencyItemTitle(id string) string {
+	if item := w.ItemById(id); item != nil {
+		return fmt.Sprintf("%s (%s)", item.Title, w.ItemStage(item).Title)
+	}
+	if archived := w.archivedItemById(id); archived != nil {
+		return fmt.Sprintf("%s (archived)", archived.Item.Title)
+	}
+	return fmt.Sprintf("Unknown item %s", id)
+}
+
+
+func (w *Board) appendDependencyChain(lines *[]string, id string, depth int, visited map[string]bool) {
+	for _, dependency := range w.Dependencies {
+		if dependency.Blocked != id {
+			continue
+		}
+
+		state := "open"
+		if w.IsBlockerResolved(dependency.Blocker) {
+			state = "resolved"
+		}
+		*lines = append(*lines, fmt.Sprintf("%s%s [%s]", strings.Repeat("    ", depth), w.dependencyItemTitle(dependency.Blocker), state))
+
+		if !visited[dependency.Blocker] {
+			visited[dependency.Blocker] = true
+			w.appendDependencyChain(lines, dependency.Blocker, depth + 1, visited)
+		}
+	}
+}
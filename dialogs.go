@@ -5,6 +5,7 @@ package main
 
 /* ================================================================================ Imports */
 import (
+	"strings"
 	"image/color"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -61,12 +62,13 @@ func ShowColorPickerDialog(title, message string, preselected color.RGBA, confir
 }
 
 
-func ShowItemDialog(dialogPrefix, title, tagEditString, description string, style ItemStyle, confirmedCallback func(title, tagEditString, description string, style ItemStyle)) {
+/* Templates (if any) prefill all fields when selected, tags from the vocabulary (if any) are appended to the tags entry */
+func ShowItemDialog(dialogPrefix, title, tagEditString, description string, style ItemStyle, templates []ItemTemplate, vocabulary []Tag, tagCounts map[string]int, confirmedCallback func(title, tagEditString, description string, style ItemStyle)) {
 	titleEntry := widget.NewEntry()
 	titleEntry.SetPlaceHolder("Title ...")
 	titleEntry.SetText(title)
 	
-	tagsEntry := widget.NewEntry()
+	tagsEntry := NewTagCompletionEntry(func() map[string]int { return tagCounts })
 	tagsEntry.SetPlaceHolder("Tag1=Value1; Tag2=Value2; ...")
 	tagsEntry.SetText(tagEditString)
 
@@ -96,8 +98,68 @@ func ShowItemDialog(dialogPrefix, title, tagEditString, description string, styl
 		},
 	)
 
+	dialogContainer := container.NewVBox()
+
+	if len(templates) > 0 {
+		templateNames := make([]string, len(templates))
+		for i, template := range templates {
+			templateNames[i] = template.Name
+		}
+
+		templateSelect := widget.NewSelect(templateNames,
+			func(selected string) {
+				for _, template := range templates {
+					if template.Name == selected {
+						titleEntry.SetText(template.TitlePrefix)
+						tagsEntry.SetText(ComposeTagEditString(template.Tags))
+						descriptionEntry.SetText(template.Description)
+						foregroundColor = template.Style.Foreground
+						backgroundColor = template.Style.Background
+						return
+					}
+				}
+			},
+		)
+		templateSelect.PlaceHolder = "Template ..."
+		dialogContainer.Add(templateSelect)
+	}
+
+	var tagsContainer fyne.CanvasObject = tagsEntry
+	if len(vocabulary) > 0 {
+		vocabularyExpressions := make([]string, len(vocabulary))
+		for i, tag := range vocabulary {
+			vocabularyExpressions[i] = tag.Expression
+		}
+
+		var vocabularySelect *widget.Select
+		vocabularySelect = widget.NewSelect(vocabularyExpressions,
+			func(selected string) {
+				if selected == "" {
+					return
+				}
+
+				tags := ParseTagEditString(tagsEntry.Text)
+				for _, tag := range tags {
+					if tag.Expression == selected {
+						vocabularySelect.ClearSelected()
+						return
+					}
+				}
+
+				tagsEntry.SetText(ComposeTagEditString(append(tags, Tag{ selected })))
+				vocabularySelect.ClearSelected()
+			},
+		)
+		vocabularySelect.PlaceHolder = "Add Tag ..."
+		tagsContainer = container.NewBorder(nil, nil, nil, vocabularySelect, tagsEntry)
+	}
+
 	buttonContainer := container.NewGridWithColumns(2, foregroundColorButton, backgroundColorButton)
-	dialogContainer := container.NewVBox(titleEntry, tagsEntry, descriptionEntry, buttonContainer, canvas.NewText("", color.Black))
+	dialogContainer.Add(titleEntry)
+	dialogContainer.Add(tagsContainer)
+	dialogContainer.Add(descriptionEntry)
+	dialogContainer.Add(buttonContainer)
+	dialogContainer.Add(canvas.NewText("", color.Black))
 
 	dialog.ShowCustomConfirm(dialogPrefix + " Item", "OK", "Cancel", dialogContainer,
 		func(confirmed bool) {
@@ -111,17 +173,40 @@ func ShowItemDialog(dialogPrefix, title, tagEditString, description string, styl
 }
 
 
-func ShowFileOpenConfirmDialog(title, text string, defaultFileURI fyne.URI, confirmedCallback func(reader fyne.URIReadCloser)) {
+func ShowSelectDialog(title, placeholder string, options []string, confirmedCallback func(index int)) {
+	selectWidget := widget.NewSelect(options, nil)
+	selectWidget.PlaceHolder = placeholder
+	if len(options) > 0 {
+		selectWidget.SetSelectedIndex(0)
+	}
+
+	dialogContainer := container.NewVBox(selectWidget, canvas.NewText("", color.Black))
+
+	dialog.ShowCustomConfirm(title, "OK", "Cancel", dialogContainer,
+		func(confirmed bool) {
+			if confirmed && confirmedCallback != nil && selectWidget.SelectedIndex() >= 0 {
+				confirmedCallback(selectWidget.SelectedIndex())
+			}
+		}, window,
+	)
+}
+
+
+func ShowReportDialog(title, text string, lines []string) {
+	dialog.ShowCustom(title, "OK", newReportContainer(text, lines), window)
+}
+
+
+func ShowReportConfirmDialog(title, text string, lines []string, confirm, dismiss string, callback func(confirmed bool)) {
+	dialog.ShowCustomConfirm(title, confirm, dismiss, newReportContainer(text, lines), callback, window)
+}
+
+
+func ShowFileOpenDialog(defaultFileURI fyne.URI, extension string, confirmedCallback func(reader fyne.URIReadCloser)) {
 	fileDialog := dialog.NewFileOpen(
 		func(reader fyne.URIReadCloser, err error) {
-			if reader != nil && err == nil {
-				ShowConfirmDialog(title, text,
-					func() {
-						if confirmedCallback != nil {
-							confirmedCallback(reader)
-						}
-					},
-				)
+			if reader != nil && err == nil && confirmedCallback != nil {
+				confirmedCallback(reader)
 			}
 		}, window,
 	)
@@ -130,12 +215,27 @@ func ShowFileOpenConfirmDialog(title, text string, defaultFileURI fyne.URI, conf
 		fileDialog.SetLocation(getParentListableURI(defaultFileURI))
 	}
 
-	fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{ ".json" }))
+	fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{ extension }))
 	fileDialog.Show()
 }
 
 
 func ShowSaveAsDialog(defaultFileURI fyne.URI, confirmedCallback func(writer fyne.URIWriteCloser)) {
+	ShowExportDialog(defaultFileURI, ".json", confirmedCallback)
+}
+
+
+func ShowExportDialog(defaultFileURI fyne.URI, extension string, confirmedCallback func(writer fyne.URIWriteCloser)) {
+	fileName := "bankan_board" + extension
+	if defaultFileURI != nil {
+		fileName = strings.TrimSuffix(defaultFileURI.Name(), defaultFileURI.Extension()) + extension
+	}
+
+	ShowFileSaveDialog(defaultFileURI, fileName, extension, confirmedCallback)
+}
+
+
+func ShowFileSaveDialog(defaultFileURI fyne.URI, defaultFileName, extension string, confirmedCallback func(writer fyne.URIWriteCloser)) {
 	fileDialog := dialog.NewFileSave(
 		func(writer fyne.URIWriteCloser, err error) {
 			if writer != nil && err == nil && confirmedCallback != nil {
@@ -144,19 +244,31 @@ func ShowSaveAsDialog(defaultFileURI fyne.URI, confirmedCallback func(writer fyn
 		}, window,
 	)
 
+	fileDialog.SetFileName(defaultFileName)
+
 	if defaultFileURI != nil {
-		fileDialog.SetFileName(defaultFileURI.Name())
 		fileDialog.SetLocation(getParentListableURI(defaultFileURI))
-	} else {
-		fileDialog.SetFileName("bankan_board.json")
 	}
 
-	fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{ ".json" }))
+	fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{ extension }))
 	fileDialog.Show()
 }
 
 
 /* ================================================================================ Private functions */
+func newReportContainer(text string, lines []string) *fyne.Container {
+	if len(lines) < 1 {
+		return container.NewVBox(widget.NewLabel(text))
+	}
+
+	reportLabel  := widget.NewLabel(strings.Join(lines, "\n"))
+	reportScroll := container.NewScroll(reportLabel)
+	reportScroll.SetMinSize(fyne.NewSize(500, 200))
+
+	return container.NewBorder(widget.NewLabel(text), nil, nil, nil, reportScroll)
+}
+
+
 func getParentListableURI(file fyne.URI) fyne.ListableURI {
 	dirURI, err := storage.Parent(file)
 	if err != nil {
@@ -5,6 +5,12 @@ package main
 
 /* ================================================================================ Imports */
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
 	"image/color"
 )
 
@@ -16,6 +22,81 @@ func ColorToRGBA(c color.Color) color.RGBA {
 }
 
 
+func ContrastColor(background color.RGBA) color.RGBA {
+	/* Use the perceived brightness (ITU-R BT.601 luma) to decide between black and white */
+	luma := (299 * int(background.R) + 587 * int(background.G) + 114 * int(background.B)) / 1000
+	if luma > 140 {
+		return color.RGBA{ 0, 0, 0, 255 }
+	}
+	return color.RGBA{ 255, 255, 255, 255 }
+}
+
+
+func NewItemId() string {
+	buffer := make([]byte, 8)
+	if _, err := rand.Read(buffer); err != nil {
+		return fmt.Sprintf("%016x", time.Now().UnixNano())
+	}
+
+	return hex.EncodeToString(buffer)
+}
+
+
+/* Returns an ID derived from the position and title of an item, for items saved without ID */
+func LegacyItemId(stageTitle string, position int, title string) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%s", stageTitle, position, title)))
+	return hex.EncodeToString(hash[:8])
+}
+
+
+/* Runs the function on the window's event goroutine, where all input callbacks run, so it cannot race with them (Fyne 2.1 offers no public way to do so), or directly without window */
+func RunOnUI(fn func()) {
+	if queue, ok := window.(interface{ QueueEvent(fn func()) }); ok {
+		queue.QueueEvent(fn)
+	} else {
+		fn()
+	}
+}
+
+
+/* Runs the function like RunOnUI and waits until it finished, returning false (and never running it) if it did not start in time */
+func RunOnUIAndWait(fn func(), timeout time.Duration) bool {
+	var mutex sync.Mutex
+	expired := false
+	done    := make(chan bool, 1)
+
+	RunOnUI(func() {
+		mutex.Lock()
+		defer mutex.Unlock()
+		if !expired {
+			fn()
+			done <- true
+		}
+	})
+
+	select {
+		case <-done:
+			return true
+		case <-time.After(timeout):
+			mutex.Lock()
+			defer mutex.Unlock()
+			expired = true
+			return len(done) > 0
+	}
+}
+
+
 func Round(f float32) float32 {
 	return float32(int(f + 0.5))
+}
+
+
+func Clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
 }
\ No newline at end of file
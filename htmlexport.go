@@ -0,0 +1,168 @@
+package main
+
+/* This file contains the export of a board into a single, self-contained and read-only HTML file */
+
+
+/* ================================================================================ Imports */
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"image/color"
+)
+
+
+/* ================================================================================ Private types */
+type htmlExportTag struct {
+	Expression, Display string
+}
+
+
+type htmlExportItem struct {
+	Title, Description     string
+	Tags                   []htmlExportTag
+	Foreground, Background string
+	Expanded               bool
+}
+
+
+type htmlExportStage struct {
+	Title string
+	Items []htmlExportItem
+}
+
+
+type htmlExportBoard struct {
+	Name   string
+	Filter string
+	Stages []htmlExportStage
+}
+
+
+/* ================================================================================ Private variables */
+/* The embedded script mirrors Board.SetTagFilter: an item is shown if any of its tags matches any filter tag (equal or a descendant statement), or if there are no filter tags */
+var htmlExportTemplate = template.Must(template.New("board").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{ .Name }}</title>
+<style>
+body { margin: 0; font-family: sans-serif; background: #202124; color: #ffffff; }
+header { display: flex; align-items: center; gap: 1em; padding: 0.5em 1em; border-bottom: 1px solid #5f6368; }
+header h1 { flex: 1; margin: 0; font-size: 1.3em; font-weight: normal; text-align: center; }
+header input { width: 20em; padding: 0.3em; }
+main { display: flex; align-items: flex-start; }
+section { flex: 1; min-width: 0; padding: 0.4em; border-right: 2px solid #5f6368; }
+section h2 { margin: 0 0 0.4em 0; font-size: 1.1em; font-style: italic; font-weight: normal; }
+article { margin-bottom: 0.3em; padding: 0.2em 0.4em; }
+article h3 { margin: 0; font-size: 1em; }
+article pre { margin: 0.3em 0 0 0; white-space: pre-wrap; word-wrap: break-word; }
+article summary { cursor: pointer; list-style: none; }
+.tag { display: inline-block; margin: 0.2em 0.2em 0 0; padding: 0 0.3em; font-size: 0.8em; font-style: italic; cursor: pointer; }
+.hidden { display: none; }
+</style>
+</head>
+<body>
+<header>
+<input id="filter" placeholder="Filter by Tag ..." value="{{ .Filter }}">
+<h1>{{ .Name }}</h1>
+</header>
+<main>
+{{- range .Stages }}
+<section>
+<h2>{{ .Title }}</h2>
+{{- range $item := .Items }}
+<article style="color: {{ .Foreground }}; background: {{ .Background }};" data-tags="{{ range .Tags }}{{ .Expression }};{{ end }}">
+<details{{ if .Expanded }} open{{ end }}>
+<summary><h3>{{ .Title }}</h3></summary>
+{{- if .Description }}
+<pre>{{ .Description }}</pre>
+{{- end }}
+</details>
+{{- range .Tags }}<span class="tag" style="color: {{ $item.Background }}; background: {{ $item.Foreground }};" data-tag="{{ .Expression }}">{{ .Display }}</span>{{ end }}
+</article>
+{{- end }}
+</section>
+{{- end }}
+</main>
+<script>
+var filter = document.getElementById("filter");
+
+function parseTags(text) {
+	return text.split(";").map(function(s) { return s.trim(); }).filter(function(s) { return s.length > 0; });
+}
+
+function matchesTag(tag, filterTag) {
+	return tag === filterTag || (tag.indexOf("=") < 0 && filterTag.indexOf("=") < 0 && tag.indexOf(filterTag + "/") === 0);
+}
+
+function applyFilter() {
+	var filterTags = parseTags(filter.value);
+	document.querySelectorAll("article").forEach(function(article) {
+		var tags  = parseTags(article.dataset.tags);
+		var match = filterTags.length < 1 || tags.some(function(tag) { return filterTags.some(function(filterTag) { return matchesTag(tag, filterTag); }); });
+		article.classList.toggle("hidden", !match);
+	});
+}
+
+document.querySelectorAll(".tag").forEach(function(label) {
+	label.addEventListener("click", function() {
+		var filterTags = parseTags(filter.value);
+		var i          = filterTags.indexOf(label.dataset.tag);
+		if (i < 0) {
+			filterTags.push(label.dataset.tag);
+		} else {
+			filterTags.splice(i, 1);
+		}
+		filter.value = filterTags.map(function(s) { return s + "; "; }).join("");
+		applyFilter();
+	});
+});
+
+filter.addEventListener("input", applyFilter);
+applyFilter();
+</script>
+</body>
+</html>
+`))
+
+
+/* ================================================================================ Public functions */
+func ExportBoardHTML(board *Board) ([]byte, error) {
+	export := htmlExportBoard{ Name: board.Name, Filter: ComposeTagEditString(board.FilterTags) }
+
+	for _, stage := range board.Stages {
+		exportStage := htmlExportStage{ Title: stage.Title }
+
+		for _, item := range stage.Items {
+			exportItem := htmlExportItem{
+				Title:       item.Title,
+				Description: item.Description,
+				Foreground:  cssColor(item.Style.Foreground),
+				Background:  cssColor(item.Style.Background),
+				Expanded:    item.Expanded,
+			}
+
+			for _, tag := range item.Tags {
+				exportItem.Tags = append(exportItem.Tags, htmlExportTag{ tag.Expression, tag.DisplayString() })
+			}
+
+			exportStage.Items = append(exportStage.Items, exportItem)
+		}
+
+		export.Stages = append(export.Stages, exportStage)
+	}
+
+	buffer := &bytes.Buffer{}
+	if err := htmlExportTemplate.Execute(buffer, export); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+
+/* ================================================================================ Private functions */
+func cssColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x%02x", c.R, c.G, c.B, c.A)
+}
\ No newline at end of file
@@ -0,0 +1,159 @@
+package main
+
+/* This file contains the export of dated items (tagged with due, start or end dates) into iCalendar files */
+
+
+/* ================================================================================ Imports */
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+
+/* ================================================================================ Constants */
+const (
+	DUE_TAG_KEY   = "due"
+	START_TAG_KEY = "start"
+	END_TAG_KEY   = "end"
+)
+
+
+/* ================================================================================ Public functions */
+/* Items with a due tag are exported as VTODO, items with a start (and optional end or due) tag as VEVENT */
+func ExportBoardICalendar(board *Board) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	stamp  := time.Now().UTC().Format("20060102T150405Z")
+
+	writeICalendarLine(buffer, "BEGIN:VCALENDAR")
+	writeICalendarLine(buffer, "VERSION:2.0")
+	writeICalendarLine(buffer, "PRODID:-//BananaJoh//BanKan//EN")
+	writeICalendarLine(buffer, "X-WR-CALNAME:" + escapeICalendarText(board.Name))
+
+	for _, stage := range board.Stages {
+		for _, item := range stage.Items {
+			due,   dueAllDay,   hasDue   := itemTagDate(item, DUE_TAG_KEY)
+			start, startAllDay, hasStart := itemTagDate(item, START_TAG_KEY)
+			end,   endAllDay,   hasEnd   := itemTagDate(item, END_TAG_KEY)
+
+			if !hasDue && !hasStart {
+				continue
+			}
+
+			categories := []string{ escapeICalendarText(stage.Title) }
+			for _, tag := range item.Tags {
+				if key, _, found := tag.KeyValue(); found && (key == DUE_TAG_KEY || key == START_TAG_KEY || key == END_TAG_KEY) {
+					continue
+				}
+				categories = append(categories, escapeICalendarText(tag.Expression))
+			}
+
+			component := "VTODO"
+			if hasStart {
+				component = "VEVENT"
+			}
+
+			writeICalendarLine(buffer, "BEGIN:" + component)
+			writeICalendarLine(buffer, "UID:" + item.Id + "@bankan")
+			writeICalendarLine(buffer, "DTSTAMP:" + stamp)
+			writeICalendarLine(buffer, "SUMMARY:" + escapeICalendarText(item.Title))
+			if item.Description != "" {
+				writeICalendarLine(buffer, "DESCRIPTION:" + escapeICalendarText(item.Description))
+			}
+			writeICalendarLine(buffer, "CATEGORIES:" + strings.Join(categories, ","))
+
+			/* Events can not have a DUE, so it ends them if there is no end tag */
+			if hasStart {
+				if !hasEnd && hasDue {
+					end, endAllDay, hasEnd = due, dueAllDay, true
+				}
+
+				writeICalendarLine(buffer, "DTSTART" + formatICalendarDate(start, startAllDay))
+				if end, valid := icalendarEventEnd(start, startAllDay, end, endAllDay); hasEnd && valid {
+					writeICalendarLine(buffer, "DTEND" + formatICalendarDate(end, startAllDay))
+				}
+			} else {
+				writeICalendarLine(buffer, "DUE" + formatICalendarDate(due, dueAllDay))
+			}
+
+			writeICalendarLine(buffer, "END:" + component)
+		}
+	}
+
+	writeICalendarLine(buffer, "END:VCALENDAR")
+
+	return buffer.Bytes(), nil
+}
+
+
+/* ================================================================================ Private functions */
+/* Returns the date of the first date tag with the given key and whether it is a whole day (without time) */
+func itemTagDate(item *Item, key string) (time.Time, bool, bool) {
+	for _, tag := range item.Tags {
+		tagKey, tagValue, found := tag.KeyValue()
+		if !found || !strings.EqualFold(tagKey, key) {
+			continue
+		}
+
+		if date, err := time.ParseInLocation("2006-01-02T15:04", tagValue, time.Local); err == nil {
+			return date, false, true
+		}
+		if date, err := time.ParseInLocation("2006-01-02 15:04", tagValue, time.Local); err == nil {
+			return date, false, true
+		}
+		if date, err := time.ParseInLocation("2006-01-02", tagValue, time.Local); err == nil {
+			return date, true, true
+		}
+	}
+	return time.Time{}, false, false
+}
+
+
+/* Returns the property parameters and value, e.g. ";VALUE=DATE:20221231" or ":20221231T100000Z" */
+func formatICalendarDate(date time.Time, allDay bool) string {
+	if allDay {
+		return ";VALUE=DATE:" + date.Format("20060102")
+	}
+	return ":" + date.UTC().Format("20060102T150405Z")
+}
+
+
+/* Converts the end to the value type of the start, as required for DTEND, and makes whole day ends (inclusive in tags) exclusive, returning false if it does not end after the start */
+func icalendarEventEnd(start time.Time, startAllDay bool, end time.Time, endAllDay bool) (time.Time, bool) {
+	if startAllDay || endAllDay {
+		end = time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1)
+	}
+	if startAllDay && !end.After(start) {
+		end = start.AddDate(0, 0, 1)
+	}
+
+	return end, end.After(start)
+}
+
+
+func escapeICalendarText(text string) string {
+	return strings.NewReplacer("\\", "\\\\", ";", "\\;", ",", "\\,", "\r\n", "\\n", "\n", "\\n").Replace(text)
+}
+
+
+/* Writes a content line, folded to lines of at most 75 octets as required by RFC 5545 */
+func writeICalendarLine(buffer *bytes.Buffer, line string) {
+	maxLength := 75
+
+	for len(line) > maxLength {
+		/* Do not split multi-byte UTF-8 sequences */
+		cut := maxLength
+		for cut > 0 && (line[cut] & 0xC0) == 0x80 {
+			cut--
+		}
+
+		fmt.Fprintf(buffer, "%s\r\n ", line[:cut])
+		line = line[cut:]
+
+		/* Continuation lines start with a space, which counts into the line length */
+		maxLength = 74
+	}
+
+	fmt.Fprintf(buffer, "%s\r\n", line)
+}
\ No newline at end of file
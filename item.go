@@ -5,6 +5,7 @@ package main
 
 /* ================================================================================ Imports */
 import (
+	"time"
 	"image/color"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
@@ -21,23 +22,34 @@ type ItemStyle struct {
 
 type Item struct {
 	widget.BaseWidget               `json:"-"`
+	Id                string
 	Title             string
 	Description       string
 	Tags              []Tag
 	Style             ItemStyle
 	Expanded          bool
+	StageEntered      *time.Time    `json:",omitempty"`
+	blocked           bool          `json:"-"`
+	focused           bool          `json:"-"`
+	selected          bool          `json:"-"`
 	dragActive        bool          `json:"-"`
 	dragStartPosition fyne.Position `json:"-"`
 	dragEndPosition   fyne.Position `json:"-"`
 }
 
 
+/* ================================================================================ Public variables */
+var DefaultItemStyle = ItemStyle{ color.RGBA{ 0, 0, 0, 255 }, color.RGBA{ 255, 255, 153, 255 } }
+var BlockedBadgeStyle = ItemStyle{ color.RGBA{ 255, 255, 255, 255 }, color.RGBA{ 204, 0, 0, 255 } }
+
+
 /* ================================================================================ Private types */
 type itemRenderer struct {
 	background        *canvas.Rectangle
 	titleLabel        *TappableCustomLabel
 	toolbarBackground *canvas.Circle
 	toolbar           *widget.Toolbar
+	blockedLabel      *TappableCustomLabel
 	tagLabels         *[]*TappableCustomLabel
 	descriptionLabel  *TappableCustomLabel
 	w                 *Item
@@ -46,8 +58,9 @@ type itemRenderer struct {
 
 /* ================================================================================ Public functions */
 func NewItem(title string, tags []Tag, description string, style ItemStyle) *Item {
-	item := &Item{ Title: title, Tags: tags, Description: description, Style: style, Expanded: false }
+	item := &Item{ Id: NewItemId(), Title: title, Tags: tags, Description: description, Style: style, Expanded: false }
 	item.ExtendBaseWidget(item)
+	item.MarkStageEntered()
 
 	return item
 }
@@ -55,7 +68,8 @@ func NewItem(title string, tags []Tag, description string, style ItemStyle) *Ite
 
 /* ================================================================================ Public methods */
 func (w *Item) NewTagLabel(tag Tag) *TappableCustomLabel {
-	return NewTappableCustomLabel(fyne.TextAlignCenter, PaintStyle{ w.Style.Background, w.Style.Foreground, color.RGBA{ 0, 0, 0, 0 }, 1 }, false, tag.DisplayString(), theme.CaptionTextSize(), fyne.TextStyle{ Italic: true }, Paddings{ 0.0, 1.0, 1.0, 0.5 }, Paddings{ 0.0, 0.0, 2.0, 2.0 },
+	style := w.TagStyle(tag)
+	return NewTappableCustomLabel(fyne.TextAlignCenter, PaintStyle{ style.Foreground, style.Background, color.RGBA{ 0, 0, 0, 0 }, 1 }, false, tag.DisplayString(), theme.CaptionTextSize(), fyne.TextStyle{ Italic: true }, Paddings{ 0.0, 1.0, 1.0, 0.5 }, Paddings{ 0.0, 0.0, 2.0, 2.0 },
 		func() {
 			board.ToggleFilterTag(tag)
 		},
@@ -63,9 +77,19 @@ func (w *Item) NewTagLabel(tag Tag) *TappableCustomLabel {
 }
 
 
+/* Tags are painted in the colors of the board's tag registry, or in the inverted item colors */
+func (w *Item) TagStyle(tag Tag) ItemStyle {
+	if board == nil {
+		return ItemStyle{ w.Style.Background, w.Style.Foreground }
+	}
+	return board.TagStyle(tag, w.Style)
+}
+
+
 func (w *Item) ShowEditItemDialog() {
-	ShowItemDialog("Edit", w.Title, ComposeTagEditString(w.Tags), w.Description, w.Style,
+	ShowItemDialog("Edit", w.Title, ComposeTagEditString(w.Tags), w.Description, w.Style, nil, board.VocabularyTags(), board.TagCounts(),
 		func(title, tagEditString, description string, style ItemStyle) {
+			board.RecordUndoStep("Edit Item " + w.Title)
 			w.Title       = title
 			w.Tags        = ParseTagEditString(tagEditString)
 			w.Description = description
@@ -76,6 +100,21 @@ func (w *Item) ShowEditItemDialog() {
 }
 
 
+func (w *Item) ShowSaveAsTemplateDialog() {
+	ShowEntryDialog("Save Item as Template", "Template name ...", w.Title,
+		func(text string) {
+			board.RecordUndoStep("Save Item Template " + text)
+			board.ItemTemplates = append(board.ItemTemplates, NewItemTemplate(text, w))
+		},
+	)
+}
+
+
+func (w *Item) ArchiveItem() {
+	board.ArchiveItem(w)
+}
+
+
 func (w *Item) ShowRemoveItemConfirmDialog() {
 	ShowConfirmDialog("Remove Item", "This will remove the item from the board.\n\nAre you sure?\n",
 		func() {
@@ -85,13 +124,24 @@ func (w *Item) ShowRemoveItemConfirmDialog() {
 }
 
 
+func (w *Item) MenuItems() []*fyne.MenuItem {
+	return []*fyne.MenuItem{
+		fyne.NewMenuItem("Edit Item",        w.ShowEditItemDialog),
+		fyne.NewMenuItem("Dependencies",     w.ShowDependenciesDialog),
+		fyne.NewMenuItem("Save as Template", w.ShowSaveAsTemplateDialog),
+		fyne.NewMenuItem("Archive Item",     w.ArchiveItem),
+		fyne.NewMenuItem("Remove Item",      w.ShowRemoveItemConfirmDialog),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Copy",             w.CopyItem),
+		fyne.NewMenuItem("Cut",              w.CutItem),
+		fyne.NewMenuItem("Paste After",      w.PasteItemsAfter),
+		fyne.NewMenuItem("Selected Items",   board.ShowBulkActionsDialog),
+	}
+}
+
+
 func (w *Item) ShowItemMenu() {
-	menu := widget.NewPopUpMenu(
-		fyne.NewMenu("Item", 
-			fyne.NewMenuItem("Edit Item", w.ShowEditItemDialog),
-			fyne.NewMenuItem("Remove Item", w.ShowRemoveItemConfirmDialog),
-		), window.Canvas(),
-	)
+	menu := widget.NewPopUpMenu(fyne.NewMenu("Item", w.MenuItems()...), window.Canvas())
 
 	stage := board.ItemStage(w)
 	if stage == nil {
@@ -102,19 +152,24 @@ func (w *Item) ShowItemMenu() {
 }
 
 
-func (w *Item) SetFilterTags(filterTags []Tag) {
-	match := false
+func (w *Item) MatchesFilterTags(filterTags []Tag) bool {
+	if len(filterTags) < 1 {
+		return true
+	}
 
 	for _, filterTag := range filterTags {
 		for _, tag := range w.Tags {
-			if tag == filterTag {
-				match = true
-				break
+			if tag.Matches(filterTag) {
+				return true
 			}
 		}
 	}
+	return false
+}
+
 
-	if match || len(filterTags) < 1 {
+func (w *Item) SetFilterTags(filterTags []Tag) {
+	if w.MatchesFilterTags(filterTags) {
 		w.Show()
 	} else {
 		w.Hide()
@@ -122,6 +177,45 @@ func (w *Item) SetFilterTags(filterTags []Tag) {
 }
 
 
+/* Moves the item like dropping it there, asking before moving blocked items too far and reporting rule violations */
+func (w *Item) MoveTo(targetStage *Stage, reference *Item, after bool) {
+	move := func() {
+		if err := board.MoveItem(w, targetStage, reference, after); err != nil {
+			ShowReportDialog("Move Item", err.Error(), nil)
+			return
+		}
+
+		/* Keep the keyboard selection, as the stages re-add their items */
+		if w.focused {
+			window.Canvas().Focus(w)
+		}
+	}
+
+	if warning := board.BlockedMoveWarning(w, targetStage); warning != "" {
+		ShowConfirmDialog("Move Blocked Item", warning + "\n\nMove anyway?\n", move)
+		return
+	}
+	move()
+}
+
+
+/* Blocked items show a badge, which is updated by the board whenever the blockers might have changed */
+func (w *Item) SetBlocked(blocked bool) {
+	if w.blocked != blocked {
+		w.blocked = blocked
+		w.Refresh()
+	}
+}
+
+
+/* Remembers the current time as the time the item entered its stage, e.g. for auto-archiving */
+func (w *Item) MarkStageEntered() {
+	now           := time.Now()
+	w.StageEntered = &now
+}
+
+
+/* Expanding is view state, so it neither records an undo step nor modifies the board */
 func (w *Item) ToggleExpanded() {
 	w.Expanded = !w.Expanded
 	w.Refresh()
@@ -145,6 +239,15 @@ func (w *Item) DragEnd() {
 		return
 	}
 
+	/* Items dropped onto the tab of another board are moved to that board */
+	absoluteEndPosition := fyne.CurrentApp().Driver().AbsolutePositionForObject(w).Add(w.dragEndPosition)
+	if tab := boardTabAtPosition(absoluteEndPosition); tab != nil {
+		if err := board.MoveItemToBoard(w, tab.Board); err != nil {
+			ShowReportDialog("Move Item", err.Error(), nil)
+		}
+		return
+	}
+
 	sourceStage := board.ItemStage(w)
 	if sourceStage == nil {
 		return
@@ -159,19 +262,14 @@ func (w *Item) DragEnd() {
 	targetStageRelativeEndPosition := fyne.NewPos(boardRelativeEndPosition.X - targetStage.Position().X, boardRelativeEndPosition.Y - targetStage.Position().Y)
 	targetItem                     := targetStage.ItemAtPosition(targetStageRelativeEndPosition)
 
+	after := true
 	if targetItem != nil {
 		targetItemRelativeEndY := targetStageRelativeEndPosition.Y - targetItem.Position().Y
 		targetItemHeightMidY   := (targetItem.Size().Height / 2)
-		if targetItemRelativeEndY < targetItemHeightMidY {
-			targetStage.InsertItem(false, targetItem, w.Title, w.Tags, w.Description, w.Style)
-		} else {
-			targetStage.InsertItem(true, targetItem, w.Title, w.Tags, w.Description, w.Style)
-		}
-	} else {
-		targetStage.AppendItem(w.Title, w.Tags, w.Description, w.Style)
+		after                   = targetItemRelativeEndY >= targetItemHeightMidY
 	}
 
-	sourceStage.RemoveItem(w)
+	w.MoveTo(targetStage, targetItem, after)
 }
 
 
@@ -180,22 +278,29 @@ func (w *Item) CreateRenderer() fyne.WidgetRenderer {
 	w.ExtendBaseWidget(w)
 
 	background := canvas.NewRectangle(w.Style.Background)
-	titleLabel := NewTappableCustomLabel(fyne.TextAlignLeading, PaintStyle{ w.Style.Foreground, color.RGBA{ 0, 0, 0, 0 }, color.RGBA{ 0, 0, 0, 0 }, 0 }, true, w.Title, theme.TextSize(), fyne.TextStyle{ Bold: true }, Paddings{ 0.0, 0.25, 1.0, 0.0 }, Paddings{ 0.0, 0.0, 0.0, 0.0 }, w.ToggleExpanded)
+	titleLabel := NewTappableCustomLabel(fyne.TextAlignLeading, PaintStyle{ w.Style.Foreground, color.RGBA{ 0, 0, 0, 0 }, color.RGBA{ 0, 0, 0, 0 }, 0 }, true, w.Title, theme.TextSize(), fyne.TextStyle{ Bold: true }, Paddings{ 0.0, 0.25, 1.0, 0.0 }, Paddings{ 0.0, 0.0, 0.0, 0.0 }, nil)
+	titleLabel.OnTapped = func() { w.TitleTapped(titleLabel.Modifier) }
 	toolbarBackground := canvas.NewCircle(color.RGBA{ 0, 0, 0, 127 })
 	toolbar           := widget.NewToolbar(widget.NewToolbarAction(theme.MoreVerticalIcon(), w.ShowItemMenu))
+	blockedLabel      := NewTappableCustomLabel(fyne.TextAlignCenter, PaintStyle{ BlockedBadgeStyle.Foreground, BlockedBadgeStyle.Background, color.RGBA{ 0, 0, 0, 0 }, 1 }, false, "Blocked", theme.CaptionTextSize(), fyne.TextStyle{ Bold: true }, Paddings{ 0.0, 1.0, 1.0, 0.5 }, Paddings{ 0.0, 0.0, 2.0, 2.0 }, w.ShowDependenciesDialog)
 	tagLabels         := make([]*TappableCustomLabel, len(w.Tags))
 
+	if !w.blocked {
+		blockedLabel.Hide()
+	}
+
 	for i, tag := range w.Tags {
 		tagLabels[i] = w.NewTagLabel(tag)
 	}
 
-	descriptionLabel := NewTappableCustomLabel(fyne.TextAlignLeading, PaintStyle{ w.Style.Foreground, color.RGBA{ 0, 0, 0, 0 }, color.RGBA{ 0, 0, 0, 0 }, 0 }, true, w.Description, theme.TextSize(), fyne.TextStyle{ Monospace: true }, Paddings{ 0.0, 1.0, 1.0, 0.5 }, Paddings{ 0.0, 0.0, 0.0, 0.0 }, w.ToggleExpanded)
+	descriptionLabel := NewTappableCustomLabel(fyne.TextAlignLeading, PaintStyle{ w.Style.Foreground, color.RGBA{ 0, 0, 0, 0 }, color.RGBA{ 0, 0, 0, 0 }, 0 }, true, w.Description, theme.TextSize(), fyne.TextStyle{ Monospace: true }, Paddings{ 0.0, 1.0, 1.0, 0.5 }, Paddings{ 0.0, 0.0, 0.0, 0.0 }, nil)
+	descriptionLabel.OnTapped = func() { w.TitleTapped(descriptionLabel.Modifier) }
 
 	if !w.Expanded {
 		descriptionLabel.Hide()
 	}
 
-	return &itemRenderer{ background, titleLabel, toolbarBackground, toolbar, &tagLabels, descriptionLabel, w }
+	return &itemRenderer{ background, titleLabel, toolbarBackground, toolbar, blockedLabel, &tagLabels, descriptionLabel, w }
 }
 
 
@@ -222,7 +327,7 @@ func (r itemRenderer) Layout(size fyne.Size) {
 	tagsBlockHeight   := float32(0)
 	tagsLineMaxHeight := float32(0)
 
-	for _, tagLabel := range *r.tagLabels {
+	for _, tagLabel := range r.flowLabels() {
 		tagSize := tagLabel.MinSize()
 
 		if tagsLineWidth > 0 && (tagsLineWidth + tagSize.Width) > size.Width {
@@ -255,7 +360,7 @@ func (r itemRenderer) MinSize() fyne.Size {
 	tagsLineMaxWidth  := float32(0)
 	tagsLineMaxHeight := float32(0)
 
-	for _, tagLabel := range *r.tagLabels {
+	for _, tagLabel := range r.flowLabels() {
 		tagSize := tagLabel.MinSize()
 
 		if tagsLineWidth > 0 && (tagsLineWidth + tagSize.Width) > maxWidth {
@@ -282,19 +387,35 @@ func (r itemRenderer) MinSize() fyne.Size {
 
 
 func (r itemRenderer) Refresh() {
-	r.background.FillColor = r.w.Style.Background
+	r.background.FillColor   = r.w.Style.Background
+	r.background.StrokeWidth = 0
+	if r.w.selected {
+		r.background.StrokeColor = theme.PrimaryColor()
+		r.background.StrokeWidth = 3
+	}
+	if r.w.focused {
+		r.background.StrokeColor = theme.FocusColor()
+		r.background.StrokeWidth = 2
+	}
 	r.background.Refresh()
 
 	r.titleLabel.Style.Foreground = r.w.Style.Foreground
 	r.titleLabel.Text             = r.w.Title
 	r.titleLabel.Refresh()
 
+	if r.w.blocked {
+		r.blockedLabel.Show()
+	} else {
+		r.blockedLabel.Hide()
+	}
+
 	tagLabelsCount := len(*r.tagLabels)
 
 	for i, tag := range r.w.Tags {
 		if i < tagLabelsCount {
-			(*r.tagLabels)[i].Style.Foreground = r.w.Style.Background
-			(*r.tagLabels)[i].Style.Background = r.w.Style.Foreground
+			style                             := r.w.TagStyle(tag)
+			(*r.tagLabels)[i].Style.Foreground = style.Foreground
+			(*r.tagLabels)[i].Style.Background = style.Background
 			(*r.tagLabels)[i].Text             = tag.DisplayString()
 			(*r.tagLabels)[i].Refresh()
 		} else {
@@ -317,15 +438,16 @@ func (r itemRenderer) Refresh() {
 
 
 func (r itemRenderer) Objects() []fyne.CanvasObject {
-	objectCount := len(*r.tagLabels) + 5
+	objectCount := len(*r.tagLabels) + 6
 	objects     := make([]fyne.CanvasObject, objectCount)
 	objects[0]   = r.background
 	objects[1]   = r.titleLabel
 	objects[2]   = r.toolbarBackground
 	objects[3]   = r.toolbar
+	objects[4]   = r.blockedLabel
 
 	for i, tagLabel := range *r.tagLabels {
-		objects[i + 4] = tagLabel
+		objects[i + 5] = tagLabel
 	}
 
 	objects[objectCount - 1] = r.descriptionLabel
@@ -335,4 +457,14 @@ func (r itemRenderer) Objects() []fyne.CanvasObject {
 
 
 func (r itemRenderer) Destroy() {
+}
+
+
+/* ================================================================================ Private rendering methods */
+/* Returns the labels flowing below the title, the blocked badge (if shown) first */
+func (r itemRenderer) flowLabels() []*TappableCustomLabel {
+	if !r.w.blocked {
+		return *r.tagLabels
+	}
+	return append([]*TappableCustomLabel{ r.blockedLabel }, *r.tagLabels...)
 }
\ No newline at end of file
@@ -0,0 +1,208 @@
+package main
+
+/* This file contains the keyboard navigation of the board: items are focusable (Tab traverses them), arrow keys select neighbouring items and Ctrl+Arrow moves the selected item */
+
+
+/* ================================================================================ Imports */
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+
+/* ================================================================================ Public methods */
+func (w *Board) FocusedItem() *Item {
+	if item, ok := window.Canvas().Focused().(*Item); ok && w.ItemStage(item) != nil {
+		return item
+	}
+	return nil
+}
+
+
+func (w *Board) FocusFirstItem() {
+	for _, stage := range w.Stages {
+		for _, item := range stage.Items {
+			if item.Visible() {
+				window.Canvas().Focus(item)
+				return
+			}
+		}
+	}
+}
+
+
+/* Selects the visible item in the direction, skipping empty stages, and keeps the selection if there is none */
+func (w *Board) FocusNeighbourItem(item *Item, stageDelta, itemDelta int) {
+	stageIndex := w.ItemStageIndex(item)
+	if stageIndex < 0 {
+		return
+	}
+
+	if itemDelta != 0 {
+		items := visibleStageItems(w.Stages[stageIndex])
+		i     := itemIndexIn(items, item) + itemDelta
+		if i >= 0 && i < len(items) {
+			window.Canvas().Focus(items[i])
+		}
+		return
+	}
+
+	row := itemIndexIn(visibleStageItems(w.Stages[stageIndex]), item)
+	for i := stageIndex + stageDelta; i >= 0 && i < len(w.Stages); i += stageDelta {
+		if items := visibleStageItems(w.Stages[i]); len(items) > 0 {
+			window.Canvas().Focus(items[Clamp(row, 0, len(items) - 1)])
+			return
+		}
+	}
+}
+
+
+/* Moves the item up/down within its stage or into the neighbouring stage at the same position, counting visible items only like the focus navigation */
+func (w *Item) MoveByKey(stageDelta, itemDelta int) {
+	stage := board.ItemStage(w)
+	if stage == nil {
+		return
+	}
+
+	if itemDelta != 0 {
+		items := visibleStageItems(stage)
+		i     := itemIndexIn(items, w) + itemDelta
+		if i >= 0 && i < len(items) {
+			w.MoveTo(stage, items[i], itemDelta > 0)
+		}
+		return
+	}
+
+	targetIndex := board.StageIndex(stage) + stageDelta
+	if targetIndex < 0 || targetIndex >= len(board.Stages) {
+		return
+	}
+
+	targetStage := board.Stages[targetIndex]
+	targetItems := visibleStageItems(targetStage)
+	if row := itemIndexIn(visibleStageItems(stage), w); row >= 0 && row < len(targetItems) {
+		w.MoveTo(targetStage, targetItems[row], false)
+	} else {
+		w.MoveTo(targetStage, nil, true)
+	}
+}
+
+
+/* ================================================================================ Public input methods */
+func (w *Item) FocusGained() {
+	w.focused = true
+	w.Refresh()
+}
+
+
+func (w *Item) FocusLost() {
+	w.focused = false
+	w.Refresh()
+}
+
+
+func (w *Item) TypedRune(r rune) {
+	stage := board.ItemStage(w)
+	if stage == nil {
+		return
+	}
+
+	switch r {
+		case 'n':
+			stage.ShowCreateItemDialog()
+		case 'e':
+			w.ShowEditItemDialog()
+		case 'm':
+			w.ShowItemMenu()
+		case 's':
+			stage.ShowStageMenu()
+		case 'd':
+			w.ShowDependenciesDialog()
+		case 'a':
+			w.ArchiveItem()
+		case 'x':
+			board.ToggleItemSelected(w)
+	}
+}
+
+
+func (w *Item) TypedKey(event *fyne.KeyEvent) {
+	switch event.Name {
+		case fyne.KeyUp:
+			board.FocusNeighbourItem(w, 0, -1)
+		case fyne.KeyDown:
+			board.FocusNeighbourItem(w, 0, 1)
+		case fyne.KeyLeft:
+			board.FocusNeighbourItem(w, -1, 0)
+		case fyne.KeyRight:
+			board.FocusNeighbourItem(w, 1, 0)
+		case fyne.KeyReturn, fyne.KeyEnter:
+			w.ShowEditItemDialog()
+		case fyne.KeySpace:
+			w.ToggleExpanded()
+		case fyne.KeyDelete:
+			w.ShowRemoveItemConfirmDialog()
+		case fyne.KeyEscape:
+			board.ClearSelection()
+			window.Canvas().Unfocus()
+	}
+}
+
+
+/* Handles Ctrl+Arrow and the clipboard shortcuts and passes all other shortcuts on to the window, as the focused widget receives them first */
+func (w *Item) TypedShortcut(shortcut fyne.Shortcut) {
+	switch shortcut.(type) {
+		case *fyne.ShortcutCopy:
+			board.CopyItems(board.ClipboardSourceItems())
+			return
+		case *fyne.ShortcutCut:
+			board.CutItems(board.ClipboardSourceItems())
+			return
+		case *fyne.ShortcutPaste:
+			w.PasteItemsAfter()
+			return
+	}
+
+	if custom, ok := shortcut.(*desktop.CustomShortcut); ok && custom.Modifier == desktop.ControlModifier {
+		switch custom.KeyName {
+			case fyne.KeyUp:
+				w.MoveByKey(0, -1)
+				return
+			case fyne.KeyDown:
+				w.MoveByKey(0, 1)
+				return
+			case fyne.KeyLeft:
+				w.MoveByKey(-1, 0)
+				return
+			case fyne.KeyRight:
+				w.MoveByKey(1, 0)
+				return
+		}
+	}
+
+	if handler, ok := window.Canvas().(fyne.Shortcutable); ok {
+		handler.TypedShortcut(shortcut)
+	}
+}
+
+
+/* ================================================================================ Private functions */
+func visibleStageItems(stage *Stage) []*Item {
+	items := []*Item{}
+	for _, item := range stage.Items {
+		if item.Visible() {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+
+func itemIndexIn(items []*Item, toFind *Item) int {
+	for i, item := range items {
+		if item == toFind {
+			return i
+		}
+	}
+	return -1
+}
@@ -5,8 +5,12 @@ package main
 
 /* ================================================================================ Imports */
 import (
+	"bytes"
 	"fmt"
 	"io"
+	"os"
+	"strings"
+	"path/filepath"
 	"image/color"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
@@ -16,6 +20,7 @@ import (
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/data/binding"
+	"fyne.io/fyne/v2/driver/desktop"
 )
 
 
@@ -26,63 +31,282 @@ const (
 
 
 /* ================================================================================ Private variables */
-var window         fyne.Window
-var board          *Board
-var boardToolbar   *widget.Toolbar
-var filterBinding  binding.String
-var boardNameLabel *CustomLabel
-var saveFileURI    fyne.URI
+var window             fyne.Window
+var board              *Board
+var boardTabs          []*BoardTab
+var activeBoardTab     *BoardTab
+var boardTabBar        *fyne.Container
+var boardContainer     *fyne.Container
+var fileToolbar        *widget.Toolbar
+var boardToolbar       *widget.Toolbar
+var filterBinding      binding.String
+var boardNameLabel     *CustomLabel
+var tagStatisticsPanel *TagStatisticsPanel
 
 
 /* ================================================================================ Private functions */
-func setSaveFileURI(uri fyne.URI) {
-	saveFileURI        = uri
+func setSaveFileURI(tab *BoardTab, uri fyne.URI) {
+	tab.SetSaveFileURI(uri)
+
+	if uri != nil {
+		AddRecentFileURI(uri)
+	}
+
+	storeBoardTabPreferences()
+	syncWindowTitle()
+}
+
+
+func syncWindowTitle() {
 	windowTitleSuffix := ""
 
-	if saveFileURI != nil {
-		fyne.CurrentApp().Preferences().SetString("saveFileURI", saveFileURI.String())
-		windowTitleSuffix = " - " + saveFileURI.Path()
-	} else {
-		fyne.CurrentApp().Preferences().SetString("saveFileURI", "")
+	if activeBoardTab != nil && activeBoardTab.SaveFileURI != nil {
+		windowTitleSuffix = " - " + activeBoardTab.SaveFileURI.Path()
 	}
 
 	window.SetTitle(WINDOW_TITLE + windowTitleSuffix)
 }
 
 
-func restorePreferences() {
-	saveFileURI = nil
+/* Stores the files of all tabs to reopen them on the next start, the file of the active tab is also the default of the command-line interface */
+func storeBoardTabPreferences() {
+	SetPreferenceURIs("openFileURIs", boardTabURIs())
+
+	if activeBoardTab != nil && activeBoardTab.SaveFileURI != nil {
+		fyne.CurrentApp().Preferences().SetString("saveFileURI", activeBoardTab.SaveFileURI.String())
+	} else {
+		fyne.CurrentApp().Preferences().SetString("saveFileURI", "")
+	}
+}
 
-	uriString := fyne.CurrentApp().Preferences().String("saveFileURI")
-	if uriString == "" {
+
+/* Runs on the UI side, while a change is reported further notifications wait for its dialog to be answered */
+func boardFileChanged(tab *BoardTab) {
+	if tab.SaveFileURI == nil || tab.FileChangeReported || boardTabIndex(tab) < 0 {
 		return
 	}
 
-	uri, err := storage.ParseURI(uriString)
-	if err != nil {
+	reader, err := storage.Reader(tab.SaveFileURI)
+	if reader == nil || err != nil {
+		return
+	}
+
+	data, err := io.ReadAll(reader)
+	reader.Close()
+	if err != nil || bytes.Equal(data, tab.SavedFileData) {
+		return
+	}
+
+	/* The file may be written only partially yet, the next change notification will follow then */
+	externalBoard := NewBoard("", nil)
+	if err := externalBoard.Load(data); err != nil {
+		return
+	}
+
+	if !tab.Modified() {
+		loadBoardData(tab, data, tab.SaveFileURI)
 		return
 	}
 
-	saveFileURI = uri
+	tab.FileChangeReported = true
+	ShowReportConfirmDialog("Board File Changed", "The file of board \"" + tab.Board.Name + "\" was changed externally, but there are unsaved local changes.\nReloading discards the local changes, keeping them overwrites the external changes on the next save.\n\nExternal changes:", DiffBoards(tab.Board, externalBoard), "Reload", "Keep Local Changes",
+		func(reload bool) {
+			tab.FileChangeReported = false
+			if reload {
+				loadBoardData(tab, data, tab.SaveFileURI)
+			} else {
+				tab.SavedFileData = data
+			}
+
+			/* Catch up on changes made while the dialog was open */
+			boardFileChanged(tab)
+		},
+	)
+}
+
+
+/* Returns the file of the active tab and the files of all tabs open on the last exit */
+func restorePreferences() (fyne.URI, []fyne.URI) {
+	activeURI, _ := storage.ParseURI(fyne.CurrentApp().Preferences().String("saveFileURI"))
+	openURIs     := PreferenceURIs("openFileURIs")
+
+	/* Preferences of older versions only contain the single board file */
+	if activeURI != nil && len(openURIs) < 1 {
+		openURIs = append(openURIs, activeURI)
+	}
+
+	return activeURI, openURIs
 }
 
 
 func windowCloseInterceptor() {
-	ShowConfirmDialog("Close Program", "This will discard any unsaved changes of the current board.\n\nAre you sure?\n", window.Close)
+	modifiedCount := 0
+	for _, tab := range boardTabs {
+		if tab.Modified() {
+			modifiedCount++
+		}
+	}
+
+	if modifiedCount < 1 {
+		window.Close()
+		return
+	}
+
+	ShowConfirmDialog("Close Program", fmt.Sprintf("This will discard the unsaved changes of %d board(s).\n\nAre you sure?\n", modifiedCount), window.Close)
 }
 
 
-func clearBoard() {
-	board.Clear()
+func addBoardTab(board *Board) *BoardTab {
+	tab := NewBoardTab(board, selectBoardTab, boardFileChanged)
+	board.OnChanged = func() { boardChanged(tab) }
+
+	boardTabs = append(boardTabs, tab)
+	boardTabBar.Add(tab.Label)
+
+	return tab
+}
+
+
+func selectBoardTab(tab *BoardTab) {
+	if activeBoardTab != nil {
+		activeBoardTab.FilterText, _ = filterBinding.Get()
+		activeBoardTab.SetActive(false)
+	}
+
+	/* The global board is the one all dialogs, menus, drag'n'drop and the local API operate on */
+	activeBoardTab = tab
+	board          = tab.Board
+	tab.SetActive(true)
+	window.Canvas().Unfocus()
+
+	/* Item tags of inactive boards may have been painted with the registry of the previously active one */
+	board.RefreshItems()
+	boardContainer.Objects = []fyne.CanvasObject{ board }
+	boardContainer.Refresh()
+
+	filterBinding.Set(tab.FilterText)
 
-	board.Name = "New Board"
 	syncBoardNameLabel()
+	syncWindowTitle()
+	storeBoardTabPreferences()
+	syncTagStatisticsPanel()
+}
 
-	setSaveFileURI(nil)
+
+func boardTabIndex(toFind *BoardTab) int {
+	for i, tab := range boardTabs {
+		if tab == toFind {
+			return i
+		}
+	}
+	return -1
+}
+
+
+func boardTabURIs() []fyne.URI {
+	uris := []fyne.URI{}
+	for _, tab := range boardTabs {
+		if tab.SaveFileURI != nil {
+			uris = append(uris, tab.SaveFileURI)
+		}
+	}
+	return uris
+}
+
+
+func boardTabByURI(uri fyne.URI) *BoardTab {
+	for _, tab := range boardTabs {
+		if tab.SaveFileURI != nil && tab.SaveFileURI.String() == uri.String() {
+			return tab
+		}
+	}
+	return nil
+}
+
+
+/* Returns the tab whose label contains the given absolute (window) position */
+func boardTabAtPosition(position fyne.Position) *BoardTab {
+	for _, tab := range boardTabs {
+		labelRect := Rectangle{ fyne.CurrentApp().Driver().AbsolutePositionForObject(tab.Label), tab.Label.Size() }
+
+		if labelRect.Contains(position) {
+			return tab
+		}
+	}
+	return nil
+}
+
+
+func openNewBoardTab() *BoardTab {
+	tab := addBoardTab(NewBoard("New Board", boardFilterChanged))
+	selectBoardTab(tab)
+
+	return tab
+}
+
+
+/* Returns the active tab if it holds an untouched new board, otherwise a new tab */
+func openBoardTabForLoading() *BoardTab {
+	if activeBoardTab != nil && activeBoardTab.SaveFileURI == nil && len(activeBoardTab.Board.Stages) < 1 && !activeBoardTab.Modified() {
+		return activeBoardTab
+	}
+	return openNewBoardTab()
+}
+
+
+func closeBoardTab(tab *BoardTab) {
+	i := boardTabIndex(tab)
+	if i < 0 {
+		return
+	}
+
+	tab.Close()
+	boardTabs = append(boardTabs[:i], boardTabs[i+1:]...)
+	boardTabBar.Remove(tab.Label)
+
+	if tab != activeBoardTab {
+		storeBoardTabPreferences()
+		return
+	}
+
+	activeBoardTab = nil
+	if len(boardTabs) < 1 {
+		openNewBoardTab()
+	} else if i < len(boardTabs) {
+		selectBoardTab(boardTabs[i])
+	} else {
+		selectBoardTab(boardTabs[i - 1])
+	}
+}
+
+
+/* Tabs opened for loading only (see openBoardTabForLoading) are closed again if loading fails */
+func closeBoardTabOpenedForLoading(tab *BoardTab) {
+	if tab.SaveFileURI == nil && len(tab.Board.Stages) < 1 && !tab.Modified() && len(boardTabs) > 1 {
+		closeBoardTab(tab)
+	}
+}
+
+
+/* Boards notify about their changes on the UI side, e.g. to keep the modified marker of the tab label up to date */
+func boardChanged(tab *BoardTab) {
+	if tab.SyncLabel() {
+		boardTabBar.Refresh()
+	}
+	if tab == activeBoardTab {
+		syncTagStatisticsPanel()
+	}
+}
+
+
+func syncTagStatisticsPanel() {
+	if tagStatisticsPanel != nil {
+		tagStatisticsPanel.Update(board)
+	}
 }
 
 
-func loadBoardReader(board *Board, reader fyne.URIReadCloser) {
+func loadBoardReader(tab *BoardTab, reader fyne.URIReadCloser) {
 	data, err := io.ReadAll(reader)
 	if err != nil {
 		fmt.Println(err)
@@ -93,37 +317,233 @@ func loadBoardReader(board *Board, reader fyne.URIReadCloser) {
 		return
 	}
 
-	board.Clear()
+	loadBoardData(tab, data, reader.URI())
+}
+
+
+func loadBoardData(tab *BoardTab, data []byte, uri fyne.URI) {
+	/* Check the data first, so that the board stays untouched if it cannot be loaded */
+	if err := NewBoard("", nil).Load(data); err != nil {
+		fmt.Println(err)
+		closeBoardTabOpenedForLoading(tab)
+		ShowReportDialog("Open Board", "The file could not be read as board:\n" + uri.Path(), []string{ err.Error() })
+		return
+	}
 
+	board := tab.Board
+	board.Clear()
 	if err := board.Load(data); err != nil {
 		fmt.Println(err)
 		return
 	}
+	board.ClearUndoHistory()
+	board.ApplyTagFilter()
+
+	if tab == activeBoardTab {
+		syncBoardNameLabel()
+	}
+
+	setSaveFileURI(tab, uri)
+	tab.SetSaved(data)
+
+	/* Items archived now are changes to the file, to be saved (or undone) */
+	board.AutoArchiveItems()
+	boardTabBar.Refresh()
+}
+
+
+func importTrelloBoardReader(tab *BoardTab, reader fyne.URIReadCloser) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	if err := reader.Close(); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	/* Check the data first, so that the board stays untouched if it cannot be imported */
+	if _, err := ImportTrelloBoard(NewBoard("", nil), data); err != nil {
+		fmt.Println(err)
+		closeBoardTabOpenedForLoading(tab)
+		ShowReportDialog("Import Trello Board", "The file could not be read as Trello board export.", []string{ err.Error() })
+		return
+	}
+
+	tab.Board.RecordUndoStep("Import Trello Board")
+	report, err := ImportTrelloBoard(tab.Board, data)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 
 	syncBoardNameLabel()
 
-	setSaveFileURI(reader.URI())
+	setSaveFileURI(tab, nil)
+
+	if len(report) > 0 {
+		ShowReportDialog("Import Trello Board", "The board was imported, but the following could not be mapped:", report)
+	}
 }
 
 
-func loadBoardURI(board *Board, uri fyne.URI) {
-	if reader, err := storage.Reader(uri); reader != nil && err == nil {
-		loadBoardReader(board, reader)
+/* Opens the board files given as arguments (e.g. by file managers), ignoring options like the process serial number passed on macOS */
+func openBoardArguments(arguments []string) {
+	for _, argument := range arguments {
+		if strings.HasPrefix(argument, "-") {
+			continue
+		}
+
+		path, err := filepath.Abs(argument)
+		if err == nil {
+			err = openBoardURI(storage.NewFileURI(path))
+		}
+		if err != nil {
+			fmt.Println(err)
+		}
+	}
+}
+
+
+func openBoardURI(uri fyne.URI) error {
+	if tab := boardTabByURI(uri); tab != nil {
+		selectBoardTab(tab)
+		return nil
+	}
+
+	reader, err := storage.Reader(uri)
+	if err != nil {
+		return err
 	}
+
+	loadBoardReader(openBoardTabForLoading(), reader)
+	return nil
 }
 
 
-func loadBoardSaveFile() {
-	if saveFileURI != nil {
-		loadBoardURI(board, saveFileURI)
+func openBoardReader(reader fyne.URIReadCloser) {
+	if tab := boardTabByURI(reader.URI()); tab != nil {
+		reader.Close()
+		selectBoardTab(tab)
+		return
+	}
+
+	loadBoardReader(openBoardTabForLoading(), reader)
+}
+
+
+func openBoardSaveFiles(activeURI fyne.URI, openURIs []fyne.URI) {
+	for _, uri := range openURIs {
+		if err := openBoardURI(uri); err != nil {
+			fmt.Println(err)
+		}
+	}
+
+	if activeURI != nil {
+		if tab := boardTabByURI(activeURI); tab != nil {
+			selectBoardTab(tab)
+		}
+	}
+}
+
+
+func openWorkspaceReader(reader fyne.URIReadCloser) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		fmt.Println(err)
+	}
+
+	if err := reader.Close(); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	boardURIs, activeURI, err := LoadWorkspace(data, reader.URI())
+	if err != nil {
+		fmt.Println(err)
+		ShowReportDialog("Open Workspace", "The file could not be read as workspace.", []string{ err.Error() })
+		return
+	}
+
+	report := []string{}
+	for _, uri := range boardURIs {
+		if err := openBoardURI(uri); err != nil {
+			report = append(report, err.Error())
+		}
+	}
+	if activeURI != nil {
+		if tab := boardTabByURI(activeURI); tab != nil {
+			selectBoardTab(tab)
+		}
+	}
+
+	AddRecentFileURI(reader.URI())
+
+	if len(report) > 0 {
+		ShowReportDialog("Open Workspace", "The workspace was opened, but the following boards could not be loaded:", report)
+	}
+}
+
+
+func saveWorkspaceWriter(writer fyne.URIWriteCloser) {
+	var activeURI fyne.URI
+	if activeBoardTab != nil {
+		activeURI = activeBoardTab.SaveFileURI
+	}
+
+	exportBoardWriter(board, writer, func(board *Board) ([]byte, error) { return WorkspaceData(writer.URI(), boardTabURIs(), activeURI) })
+
+	AddRecentFileURI(writer.URI())
+}
+
+
+func openRecentFile(uri fyne.URI) {
+	if IsWorkspaceURI(uri) {
+		if reader, err := storage.Reader(uri); err == nil {
+			openWorkspaceReader(reader)
+			return
+		}
+	} else if err := openBoardURI(uri); err == nil {
+		return
+	}
+
+	RemoveRecentFileURI(uri)
+	ShowReportDialog("Open Recent File", "The file could not be opened and was removed from the recent files:\n" + uri.Path(), nil)
+}
+
+
+func saveBoardWriter(tab *BoardTab, writer fyne.URIWriteCloser) {
+	tab.Board.AutoArchiveItems()
+
+	data, err := tab.Board.Data()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if written, err := writer.Write(data); err != nil || written != len(data) {
+		fmt.Println(err)
+	}
+
+	if err = writer.Close(); err != nil {
+		fmt.Println(err)
+		return
 	}
+
+	setSaveFileURI(tab, writer.URI())
+	tab.SetSaved(data)
+	boardTabBar.Refresh()
+
+	exportBoardICalendarFile(tab.Board)
 }
 
 
-func saveBoardWriter(board *Board, writer fyne.URIWriteCloser) {
-	data, err := board.Data()
+func exportBoardWriter(board *Board, writer fyne.URIWriteCloser, export func(board *Board) ([]byte, error)) {
+	data, err := export(board)
 	if err != nil {
 		fmt.Println(err)
+		writer.Close()
 		return
 	}
 
@@ -133,16 +553,26 @@ func saveBoardWriter(board *Board, writer fyne.URIWriteCloser) {
 
 	if err = writer.Close(); err != nil {
 		fmt.Println(err)
+	}
+}
+
+
+func exportBoardICalendarFile(board *Board) {
+	if board.ICalendarFile == "" {
 		return
 	}
 
-	setSaveFileURI(writer.URI())
+	if writer, err := storage.Writer(storage.NewFileURI(board.ICalendarFile)); writer != nil && err == nil {
+		exportBoardWriter(board, writer, ExportBoardICalendar)
+	} else {
+		fmt.Println(err)
+	}
 }
 
 
-func saveBoardURI(board *Board, uri fyne.URI) {
+func saveBoardURI(tab *BoardTab, uri fyne.URI) {
 	if writer, err := storage.Writer(uri); writer != nil && err == nil {
-		saveBoardWriter(board, writer)
+		saveBoardWriter(tab, writer)
 	}
 }
 
@@ -154,34 +584,202 @@ func syncBoardNameLabel() {
 
 
 func newButtonTapped() {
-	ShowConfirmDialog("Create Empty Board", "This will discard the current board.\n\nAre you sure?\n", clearBoard)
+	templates     := BoardTemplates()
+	templateNames := make([]string, len(templates))
+	for i, template := range templates {
+		templateNames[i] = template.Name
+	}
+
+	ShowSelectDialog("New Board", "Template ...", templateNames,
+		func(index int) {
+			tab := openNewBoardTab()
+			templates[index].Apply(tab.Board)
+			tab.SetSaved(nil)
+		},
+	)
 }
 
 
 func loadButtonTapped() {
-	ShowFileOpenConfirmDialog("Load Board", "This will discard the current board.\n\nAre you sure?\n", saveFileURI,
-		func(reader fyne.URIReadCloser) { loadBoardReader(board, reader) },
-	)
+	ShowFileOpenDialog(activeBoardTab.SaveFileURI, ".json", openBoardReader)
 }
 
 
 func saveAsButtonTapped() {
-	ShowSaveAsDialog(saveFileURI, func(writer fyne.URIWriteCloser) { saveBoardWriter(board, writer) })
+	tab := activeBoardTab
+	ShowSaveAsDialog(tab.SaveFileURI, func(writer fyne.URIWriteCloser) { saveBoardWriter(tab, writer) })
 }
 
 
 func saveButtonTapped() {
-	if saveFileURI != nil {
-		saveBoardURI(board, saveFileURI)
+	if activeBoardTab.SaveFileURI != nil {
+		saveBoardURI(activeBoardTab, activeBoardTab.SaveFileURI)
 	} else {
 		saveAsButtonTapped()
 	}
 }
 
 
+func recentButtonTapped() {
+	menuItems := []*fyne.MenuItem{}
+
+	for _, uri := range RecentFileURIs() {
+		recentURI := uri
+		menuItems  = append(menuItems, fyne.NewMenuItem(recentURI.Path(), func() { openRecentFile(recentURI) }))
+	}
+	if len(menuItems) < 1 {
+		menuItems = append(menuItems, &fyne.MenuItem{ Label: "No Recent Files", Disabled: true })
+	}
+
+	menuItems = append(menuItems,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Open Workspace",     func() { ShowFileOpenDialog(activeBoardTab.SaveFileURI, WORKSPACE_FILE_EXTENSION, openWorkspaceReader) }),
+		fyne.NewMenuItem("Save Workspace As",  func() { ShowExportDialog(activeBoardTab.SaveFileURI, WORKSPACE_FILE_EXTENSION, saveWorkspaceWriter) }),
+		fyne.NewMenuItem("Clear Recent Files", ClearRecentFileURIs),
+	)
+
+	menu := widget.NewPopUpMenu(fyne.NewMenu("Recent", menuItems...), window.Canvas())
+	menu.ShowAtPosition(fyne.NewPos(fileToolbar.Position().X, fileToolbar.Position().Y + fileToolbar.Size().Height))
+}
+
+
+func closeButtonTapped() {
+	tab := activeBoardTab
+	if !tab.Modified() {
+		closeBoardTab(tab)
+		return
+	}
+
+	ShowConfirmDialog("Close Board", "This will discard the unsaved changes of the current board.\n\nAre you sure?\n", func() { closeBoardTab(tab) })
+}
+
+
+func showImportTrelloBoardDialog() {
+	ShowFileOpenDialog(activeBoardTab.SaveFileURI, ".json",
+		func(reader fyne.URIReadCloser) { importTrelloBoardReader(openBoardTabForLoading(), reader) },
+	)
+}
+
+
+func showExportHTMLDialog() {
+	ShowExportDialog(activeBoardTab.SaveFileURI, ".html", func(writer fyne.URIWriteCloser) { exportBoardWriter(board, writer, ExportBoardHTML) })
+}
+
+
+func showExportPNGDialog() {
+	ShowExportDialog(activeBoardTab.SaveFileURI, ".png", func(writer fyne.URIWriteCloser) { exportBoardWriter(board, writer, ExportBoardPNG) })
+}
+
+
+func showExportPDFDialog() {
+	ShowExportDialog(activeBoardTab.SaveFileURI, ".pdf", func(writer fyne.URIWriteCloser) { exportBoardWriter(board, writer, ExportBoardPDF) })
+}
+
+
+func showExportICalendarDialog() {
+	ShowExportDialog(activeBoardTab.SaveFileURI, ".ics", func(writer fyne.URIWriteCloser) { exportBoardWriter(board, writer, ExportBoardICalendar) })
+}
+
+
+func showICalendarAutoExportDialog() {
+	ShowEntryDialog("iCalendar Auto-Export (on every save)", "Path to .ics file, empty to disable ...", board.ICalendarFile,
+		func(text string) {
+			board.RecordUndoStep("Edit iCalendar Auto-Export")
+			board.ICalendarFile = strings.TrimSpace(text)
+			exportBoardICalendarFile(board)
+		},
+	)
+}
+
+
+func startLocalAPIFromPreferences() {
+	address := fyne.CurrentApp().Preferences().String("localAPIAddress")
+	if address == "" {
+		StopLocalAPI()
+		return
+	}
+
+	if err := StartLocalAPI(address); err != nil {
+		fmt.Println(err)
+		ShowReportDialog("Local API", "The local API could not be started.", []string{ err.Error() })
+	}
+}
+
+
+func showLocalAPISettingsDialog() {
+	address := fyne.CurrentApp().Preferences().String("localAPIAddress")
+	if address == "" {
+		address = DEFAULT_API_ADDRESS
+	}
+
+	ShowEntryDialog("Local API (empty to disable)", "Address, e.g. " + DEFAULT_API_ADDRESS + " ...", address,
+		func(text string) {
+			fyne.CurrentApp().Preferences().SetString("localAPIAddress", strings.TrimSpace(text))
+			startLocalAPIFromPreferences()
+
+			/* Clients need the token of this session, which is copied for convenience */
+			if apiServer != nil {
+				window.Clipboard().SetContent(LocalAPIToken())
+				ShowReportDialog("Local API", "Requests need the token of this session in the " + API_TOKEN_HEADER + " header (copied to the clipboard):", []string{ LocalAPIToken() })
+			}
+		},
+	)
+}
+
+
+func syncTodoTxt() {
+	if board.TodoTxt == nil || board.TodoTxt.File == "" {
+		/* Only synchronize if a file was chosen, instead of asking again and again */
+		board.ShowTodoTxtSettingsDialog(
+			func() {
+				if board.TodoTxt.File != "" {
+					syncTodoTxt()
+				}
+			},
+		)
+		return
+	}
+
+	uri, err := storage.ParseURI(board.TodoTxt.File)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	created, updated, removed := 0, 0, 0
+	board.RecordUndoStep("Sync todo.txt")
+
+	/* The file may not exist yet on the first synchronization, which is just an export then */
+	if reader, err := storage.Reader(uri); reader != nil && err == nil {
+		data, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			fmt.Println(err)
+			return
+		}
+		created, updated, removed = ImportTodoTxt(board, data, board.TodoTxt)
+	}
+
+	writer, err := storage.Writer(uri)
+	if writer == nil || err != nil {
+		fmt.Println(err)
+		return
+	}
+	exportBoardWriter(board, writer, func(board *Board) ([]byte, error) { return ExportTodoTxt(board, board.TodoTxt), nil })
+
+	ShowReportDialog("Sync todo.txt", fmt.Sprintf("Synchronized with %s:\n%d item(s) created, %d item(s) updated, %d item(s) removed.", uri.Path(), created, updated, removed), nil)
+}
+
+
+func showTodoTxtSettingsDialog() {
+	board.ShowTodoTxtSettingsDialog(nil)
+}
+
+
 func showEditBoardNameDialog() {
 	ShowEntryDialog("Edit Board Name", "Name ...", board.Name,
 		func(text string) {
+			board.RecordUndoStep("Edit Board Name")
 			board.Name = text
 			syncBoardNameLabel()
 		},
@@ -189,11 +787,140 @@ func showEditBoardNameDialog() {
 }
 
 
-func showBoardMenu() {
-	menu := widget.NewPopUpMenu(
-		fyne.NewMenu("Board", fyne.NewMenuItem("Edit Board Name", showEditBoardNameDialog)),
-		window.Canvas(),
+func showSaveBoardTemplateDialog() {
+	ShowEntryDialog("Save Board as Template", "Template name ...", board.Name,
+		func(text string) {
+			template  := NewBoardTemplate(text, board)
+			templates := UserBoardTemplates()
+
+			for i := range templates {
+				if templates[i].Name == template.Name {
+					templates[i] = template
+					SetUserBoardTemplates(templates)
+					return
+				}
+			}
+			SetUserBoardTemplates(append(templates, template))
+		},
 	)
+}
+
+
+func showEditTagVocabularyDialog() {
+	ShowEntryDialog("Edit Tag Vocabulary", "Tag1=Value1; Tag2; ...", ComposeTagEditString(board.TagVocabulary),
+		func(text string) {
+			board.RecordUndoStep("Edit Tag Vocabulary")
+			board.TagVocabulary = ParseTagEditString(text)
+		},
+	)
+}
+
+
+/* Lists the board templates saved by the user and the item templates of the current board */
+func showRemoveTemplateDialog() {
+	boardTemplates := UserBoardTemplates()
+	templateNames  := []string{}
+
+	for _, template := range boardTemplates {
+		templateNames = append(templateNames, "Board Template: " + template.Name)
+	}
+	for _, template := range board.ItemTemplates {
+		templateNames = append(templateNames, "Item Template: " + template.Name)
+	}
+
+	if len(templateNames) < 1 {
+		ShowReportDialog("Remove Template", "There are neither saved board templates nor item templates of this board.", nil)
+		return
+	}
+
+	ShowSelectDialog("Remove Template", "Template ...", templateNames,
+		func(index int) {
+			if index < len(boardTemplates) {
+				SetUserBoardTemplates(append(boardTemplates[:index], boardTemplates[index+1:]...))
+			} else {
+				index -= len(boardTemplates)
+				board.RecordUndoStep("Remove Item Template")
+				board.ItemTemplates = append(board.ItemTemplates[:index], board.ItemTemplates[index+1:]...)
+			}
+		},
+	)
+}
+
+
+func showArchiveDialog() {
+	board.ShowArchiveDialog()
+}
+
+
+func showAutoArchiveSettingsDialog() {
+	board.ShowAutoArchiveSettingsDialog()
+}
+
+
+func showBlockedWarningStageDialog() {
+	board.ShowBlockedWarningStageDialog()
+}
+
+
+func showTagManagerDialog() {
+	board.ShowTagManagerDialog()
+}
+
+
+func showRefactorTagsDialog() {
+	board.ShowRefactorTagsDialog()
+}
+
+
+func showTagTreeDialog() {
+	board.ShowTagTreeDialog()
+}
+
+
+func toggleTagStatisticsPanel() {
+	tagStatisticsPanel.Toggle()
+}
+
+
+func showExportTagStatisticsDialog() {
+	ShowExportDialog(activeBoardTab.SaveFileURI, ".csv", func(writer fyne.URIWriteCloser) { exportBoardWriter(board, writer, ExportBoardTagStatisticsCSV) })
+}
+
+
+func boardMenuItems() []*fyne.MenuItem {
+	return []*fyne.MenuItem{
+		fyne.NewMenuItem("Undo",                   undoShortcutTyped),
+		fyne.NewMenuItem("Redo",                   redoShortcutTyped),
+		fyne.NewMenuItem("Selected Items",         showBulkActionsDialog),
+		fyne.NewMenuItem("Save Current Filter",    showSaveFilterDialog),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Edit Board Name",        showEditBoardNameDialog),
+		fyne.NewMenuItem("Edit Tag Vocabulary",    showEditTagVocabularyDialog),
+		fyne.NewMenuItem("Tag Manager",            showTagManagerDialog),
+		fyne.NewMenuItem("Rename / Merge Tags",    showRefactorTagsDialog),
+		fyne.NewMenuItem("Tag Tree",               showTagTreeDialog),
+		fyne.NewMenuItem("Tag Statistics",         toggleTagStatisticsPanel),
+		fyne.NewMenuItem("Export Tag Statistics",  showExportTagStatisticsDialog),
+		fyne.NewMenuItem("Save as Board Template", showSaveBoardTemplateDialog),
+		fyne.NewMenuItem("Remove Template",        showRemoveTemplateDialog),
+		fyne.NewMenuItem("Archive",                showArchiveDialog),
+		fyne.NewMenuItem("Auto-Archive Settings",  showAutoArchiveSettingsDialog),
+		fyne.NewMenuItem("Blocked Item Warning",   showBlockedWarningStageDialog),
+		fyne.NewMenuItem("Import Trello Board",    showImportTrelloBoardDialog),
+		fyne.NewMenuItem("Export HTML",            showExportHTMLDialog),
+		fyne.NewMenuItem("Export PNG Snapshot",    showExportPNGDialog),
+		fyne.NewMenuItem("Export PDF Snapshot",    showExportPDFDialog),
+		fyne.NewMenuItem("Export iCalendar",       showExportICalendarDialog),
+		fyne.NewMenuItem("iCalendar Auto-Export",  showICalendarAutoExportDialog),
+		fyne.NewMenuItem("Sync todo.txt",          syncTodoTxt),
+		fyne.NewMenuItem("todo.txt Settings",      showTodoTxtSettingsDialog),
+		fyne.NewMenuItem("Local API Settings",     showLocalAPISettingsDialog),
+	}
+}
+
+
+func showBoardMenu() {
+	menu := widget.NewPopUpMenu(fyne.NewMenu("Board", boardMenuItems()...), window.Canvas())
 	menu.ShowAtPosition(fyne.NewPos(boardToolbar.Position().X - menu.Size().Width + 50, boardToolbar.Position().Y + menu.Size().Height))
 }
 
@@ -203,10 +930,110 @@ func boardFilterChanged(tagEditString string) {
 }
 
 
+/* Bindings notify their listeners on a goroutine of their own */
 func filterBindingChanged() {
-	if text, err := filterBinding.Get(); err == nil {
-		board.SetTagFilter(text)
+	RunOnUI(func() {
+		if text, err := filterBinding.Get(); err == nil {
+			board.SetTagFilter(text)
+			syncTagStatisticsPanel()
+		}
+	})
+}
+
+
+func createStageButtonTapped() {
+	board.ShowCreateStageDialog()
+}
+
+
+func undoShortcutTyped() {
+	if board.Undo() == "" {
+		ShowReportDialog("Undo", "There is nothing to undo.", nil)
+	}
+}
+
+
+func redoShortcutTyped() {
+	if board.Redo() == "" {
+		ShowReportDialog("Redo", "There is nothing to redo.", nil)
+	}
+}
+
+
+func showBulkActionsDialog() {
+	board.ShowBulkActionsDialog()
+}
+
+
+func showSaveFilterDialog() {
+	board.ShowSaveFilterDialog()
+}
+
+
+/* Creates the item in the stage of the selected item, or in the first stage if none is selected */
+func createItemShortcutTyped() {
+	if item := board.FocusedItem(); item != nil {
+		board.ItemStage(item).ShowCreateItemDialog()
+	} else if len(board.Stages) > 0 {
+		board.Stages[0].ShowCreateItemDialog()
+	}
+}
+
+
+func selectNeighbourBoardTab(offset int) {
+	if len(boardTabs) > 0 {
+		selectBoardTab(boardTabs[(boardTabIndex(activeBoardTab) + offset + len(boardTabs)) % len(boardTabs)])
+	}
+}
+
+
+/* Typed keys reach the canvas only if no item (or entry) is focused, so arrow keys start the keyboard selection */
+func boardKeyTyped(event *fyne.KeyEvent) {
+	switch event.Name {
+		case fyne.KeyUp, fyne.KeyDown, fyne.KeyLeft, fyne.KeyRight:
+			board.FocusFirstItem()
+		case fyne.KeyEscape:
+			board.ClearSelection()
+	}
+}
+
+
+func addWindowShortcuts(filterEntry *TagCompletionEntry) {
+	shortcuts := []struct {
+		keyName  fyne.KeyName
+		modifier desktop.Modifier
+		handler  func()
+	}{
+		{ fyne.KeyN,        desktop.ControlModifier,                         newButtonTapped },
+		{ fyne.KeyO,        desktop.ControlModifier,                         loadButtonTapped },
+		{ fyne.KeyR,        desktop.ControlModifier,                         recentButtonTapped },
+		{ fyne.KeyS,        desktop.ControlModifier,                         saveButtonTapped },
+		{ fyne.KeyS,        desktop.ControlModifier | desktop.ShiftModifier, saveAsButtonTapped },
+		{ fyne.KeyW,        desktop.ControlModifier,                         closeButtonTapped },
+		{ fyne.KeyPageDown, desktop.ControlModifier,                         func() { selectNeighbourBoardTab(1) } },
+		{ fyne.KeyPageUp,   desktop.ControlModifier,                         func() { selectNeighbourBoardTab(-1) } },
+		{ fyne.KeyF,        desktop.ControlModifier,                         func() { window.Canvas().Focus(filterEntry) } },
+		{ fyne.KeyI,        desktop.ControlModifier,                         createItemShortcutTyped },
+		{ fyne.KeyN,        desktop.ControlModifier | desktop.ShiftModifier, createStageButtonTapped },
+		{ fyne.KeyB,        desktop.ControlModifier,                         showBoardMenu },
+		{ fyne.KeyZ,        desktop.ControlModifier,                         undoShortcutTyped },
+		{ fyne.KeyZ,        desktop.ControlModifier | desktop.ShiftModifier, redoShortcutTyped },
+		{ fyne.KeyY,        desktop.ControlModifier,                         redoShortcutTyped },
+		{ fyne.KeyE,        desktop.ControlModifier,                         showBulkActionsDialog },
+		{ fyne.KeyP,        desktop.ControlModifier,                         ShowCommandPalette },
+		{ fyne.KeyT,        desktop.ControlModifier,                         toggleTagStatisticsPanel },
+	}
+
+	/* Without focused item, the clipboard shortcuts apply to the selected items and paste into the first stage */
+	window.Canvas().AddShortcut(&fyne.ShortcutCopy{},  func(fyne.Shortcut) { board.CopyItems(board.ClipboardSourceItems()) })
+	window.Canvas().AddShortcut(&fyne.ShortcutCut{},   func(fyne.Shortcut) { board.CutItems(board.ClipboardSourceItems()) })
+	window.Canvas().AddShortcut(&fyne.ShortcutPaste{}, func(fyne.Shortcut) { board.PasteItemsAtFocus() })
+
+	for _, shortcut := range shortcuts {
+		handler := shortcut.handler
+		window.Canvas().AddShortcut(&desktop.CustomShortcut{ KeyName: shortcut.keyName, Modifier: shortcut.modifier }, func(fyne.Shortcut) { handler() })
 	}
+	window.Canvas().SetOnTypedKey(boardKeyTyped)
 }
 
 
@@ -214,43 +1041,65 @@ func main() {
 	application := app.NewWithID("de.bananajoh.bankan")
 	application.SetIcon(theme.FyneLogo())
 
-	window = application.NewWindow(WINDOW_TITLE)
-	window.SetCloseIntercept(windowCloseInterceptor)
+	activeURI, openURIs := restorePreferences()
 
-	restorePreferences()
+	/* Run without window if a command is given */
+	if IsCommandLineCommand(os.Args[1:]) {
+		os.Exit(RunCommandLine(os.Args[1:], activeURI))
+	}
 
-	board = NewBoard("New Board", boardFilterChanged)
+	window = application.NewWindow(WINDOW_TITLE)
+	window.SetCloseIntercept(windowCloseInterceptor)
 
-	fileToolbar := widget.NewToolbar(
+	fileToolbar = widget.NewToolbar(
 		widget.NewToolbarAction(theme.DocumentIcon(),     newButtonTapped),
 		widget.NewToolbarAction(theme.FolderOpenIcon(),   loadButtonTapped),
+		widget.NewToolbarAction(theme.HistoryIcon(),      recentButtonTapped),
 		widget.NewToolbarAction(theme.DownloadIcon(),     saveAsButtonTapped),
 		widget.NewToolbarAction(theme.DocumentSaveIcon(), saveButtonTapped),
 	)
 	filterBinding = binding.NewString()
 	filterBinding.AddListener(binding.NewDataListener(filterBindingChanged))
 
-	filterEntry := widget.NewEntryWithData(filterBinding)
+	filterEntry := NewTagCompletionEntry(func() map[string]int { return board.TagCounts() })
+	filterEntry.Bind(filterBinding)
 	filterEntry.SetPlaceHolder("Filter by Tag ...")
 
 	leftHeaderContainer := container.NewGridWithColumns(2, fileToolbar, filterEntry)
 
-	boardNameLabel      = NewCustomLabel(fyne.TextAlignCenter, PaintStyle{ color.RGBA{ 255, 255, 255, 255 }, color.RGBA{ 0, 0, 0, 0 }, color.RGBA{ 0, 0, 0, 0 }, 0 }, false, board.Name, theme.TextSubHeadingSize(), fyne.TextStyle{}, Paddings{ 1.0, 1.0, 1.0, 1.0 }, Paddings{ 0.0, 0.0, 0.0, 0.0 })
+	boardNameLabel      = NewCustomLabel(fyne.TextAlignCenter, PaintStyle{ color.RGBA{ 255, 255, 255, 255 }, color.RGBA{ 0, 0, 0, 0 }, color.RGBA{ 0, 0, 0, 0 }, 0 }, false, "", theme.TextSubHeadingSize(), fyne.TextStyle{}, Paddings{ 1.0, 1.0, 1.0, 1.0 }, Paddings{ 0.0, 0.0, 0.0, 0.0 })
 	boardNameContainer := container.NewHBox(layout.NewSpacer(), boardNameLabel, layout.NewSpacer())
 
 	boardToolbar = widget.NewToolbar(
-		widget.NewToolbarAction(theme.FolderNewIcon(),    board.ShowCreateStageDialog),
+		widget.NewToolbarAction(theme.FolderNewIcon(),    createStageButtonTapped),
 		widget.NewToolbarAction(theme.MoreVerticalIcon(), showBoardMenu),
 	)
 
 	toolbarContainer   := container.NewBorder(nil, nil, leftHeaderContainer, boardToolbar, boardNameContainer)
 	headerBarContainer := container.NewVBox(toolbarContainer, widget.NewSeparator())
-	windowContainer    := container.NewBorder(headerBarContainer, nil, nil, nil, board)
 
-	loadBoardSaveFile()
+	/* The tabs are placed at the bottom to keep the board position (which the stage and item menus rely on) unchanged */
+	boardTabBar = container.NewHBox()
+	tabToolbar := widget.NewToolbar(
+		widget.NewToolbarAction(theme.ContentAddIcon(), newButtonTapped),
+		widget.NewToolbarAction(theme.CancelIcon(),     closeButtonTapped),
+	)
+	tabBarContainer := container.NewVBox(widget.NewSeparator(), container.NewBorder(nil, nil, nil, tabToolbar, container.NewHScroll(boardTabBar)))
+
+	boardContainer     = container.NewMax()
+	tagStatisticsPanel = NewTagStatisticsPanel()
+	windowContainer   := container.NewBorder(headerBarContainer, tabBarContainer, nil, tagStatisticsPanel.Container, boardContainer)
+
+	openBoardSaveFiles(activeURI, openURIs)
+	openBoardArguments(os.Args[1:])
+	if len(boardTabs) < 1 {
+		openNewBoardTab()
+	}
+	startLocalAPIFromPreferences()
 
 	window.SetContent(windowContainer)
+	addWindowShortcuts(filterEntry)
 	window.Resize(fyne.NewSize(1200, 700))
 	window.CenterOnScreen()
 	window.ShowAndRun()
-}
\ No newline at end of file
+}
@@ -0,0 +1,532 @@
+package main
+
+/* This file contains the three-way merge of board versions at stage and item level, matching items by their ID */
+
+
+/* ================================================================================ Imports */
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"image/color"
+)
+
+
+/* ================================================================================ Constants */
+const (
+	CONFLICTS_STAGE_TITLE = "Conflicts"
+)
+
+
+/* ================================================================================ Private types */
+/* The state of an item in one board version, with its fields as JSON to compare and merge them generically */
+type mergeItemState struct {
+	Stage  string
+	Fields map[string]json.RawMessage
+}
+
+
+/* ================================================================================ Public functions */
+/* Merges the changes from base to ours and from base to theirs, true conflicts are kept as in ours and described by items in the conflicts stage */
+func MergeBoards(base, ours, theirs *Board) (*Board, int, error) {
+	merged    := NewBoard(ours.Name, nil)
+	conflicts := []*Item{}
+
+	if ours.Name == base.Name {
+		merged.Name = theirs.Name
+	}
+
+	/* Take all other board fields (e.g. settings) like the name, preferring ours on conflicts */
+	baseFields,   err1 := jsonFields(base)
+	oursFields,   err2 := jsonFields(ours)
+	theirsFields, err3 := jsonFields(theirs)
+	if err := firstError(err1, err2, err3); err != nil {
+		return nil, 0, err
+	}
+	mergedFields := map[string]json.RawMessage{}
+	for _, key := range unionKeys(baseFields, oursFields, theirsFields) {
+		if key == "Name" || key == "Stages" || key == "Archive" || key == "Dependencies" {
+			continue
+		}
+		if value, _ := mergeJSONValue(baseFields[key], oursFields[key], theirsFields[key]); value != nil {
+			mergedFields[key] = value
+		}
+	}
+	if err := unmarshalJSONFields(mergedFields, merged); err != nil {
+		return nil, 0, err
+	}
+
+	baseItems,   err1 := mergeItemStates(base)
+	oursItems,   err2 := mergeItemStates(ours)
+	theirsItems, err3 := mergeItemStates(theirs)
+	if err := firstError(err1, err2, err3); err != nil {
+		return nil, 0, err
+	}
+
+	/* Resolve the state (stage and fields) of every item */
+	mergedItems := map[string]*mergeItemState{}
+	for _, id := range unionItemIds(baseItems, oursItems, theirsItems) {
+		baseItem, oursItem, theirsItem := baseItems[id], oursItems[id], theirsItems[id]
+
+		switch {
+			case oursItem == nil && theirsItem == nil:
+				/* Removed on both sides */
+
+			case oursItem == nil || theirsItem == nil:
+				remaining := oursItem
+				removedBy := "theirs"
+				if remaining == nil {
+					remaining = theirsItem
+					removedBy = "ours"
+				}
+
+				if baseItem == nil {
+					/* Added on one side */
+					mergedItems[id] = remaining
+				} else if !mergeItemStateEqual(baseItem, remaining) {
+					/* Removed on one side, but changed on the other one */
+					mergedItems[id] = remaining
+					conflicts = append(conflicts, newConflictItem(id, remaining, []string{ fmt.Sprintf("Removed in %s, but changed in the other version", removedBy) }))
+				}
+
+			default:
+				if baseItem == nil {
+					baseItem = &mergeItemState{ Fields: map[string]json.RawMessage{} }
+				}
+
+				mergedItem  := &mergeItemState{ Fields: map[string]json.RawMessage{} }
+				differences := []string{}
+
+				var stageConflict bool
+				mergedItem.Stage, stageConflict = mergeString(baseItem.Stage, oursItem.Stage, theirsItem.Stage)
+				if stageConflict {
+					differences = append(differences, fmt.Sprintf("Stage: \"%s\" (ours) / \"%s\" (theirs)", oursItem.Stage, theirsItem.Stage))
+				}
+
+				for _, key := range unionKeys(baseItem.Fields, oursItem.Fields, theirsItem.Fields) {
+					value, conflict := mergeJSONValue(baseItem.Fields[key], oursItem.Fields[key], theirsItem.Fields[key])
+					mergedItem.Fields[key] = value
+
+					/* Differences in the expanded state are not worth a conflict */
+					if conflict && key != "Expanded" {
+						differences = append(differences, fmt.Sprintf("%s: %s (ours) / %s (theirs)", key, oursItem.Fields[key], theirsItem.Fields[key]))
+					}
+				}
+
+				mergedItems[id] = mergedItem
+				if len(differences) > 0 {
+					conflicts = append(conflicts, newConflictItem(id, oursItem, differences))
+				}
+		}
+	}
+
+	merged.Archive      = mergeArchives(ours, theirs, mergedItems)
+	merged.Dependencies = mergeDependencies(base, ours, theirs, merged.Archive, mergedItems)
+
+	/* Resolve the stages, keeping removed stages if items were moved into them on the other side */
+	stageTitles := mergeStageTitles(base, ours, theirs, mergedItems)
+
+	for _, title := range stageTitles {
+		merged.AppendStage(title)
+		stage := merged.Stages[len(merged.Stages) - 1]
+
+		if err := mergeStageFields(stage, base, ours, theirs); err != nil {
+			return nil, 0, err
+		}
+
+		for _, id := range mergeStageItemOrder(title, base, ours, theirs, mergedItems) {
+			item := NewItem("", nil, "", DefaultItemStyle)
+			if err := unmarshalJSONFields(mergedItems[id].Fields, item); err != nil {
+				return nil, 0, err
+			}
+			item.Id = id
+			stage.PlaceItem(item, nil, true)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		stage := merged.StageByTitle(CONFLICTS_STAGE_TITLE)
+		if stage == nil {
+			merged.AppendStage(CONFLICTS_STAGE_TITLE)
+			stage = merged.Stages[len(merged.Stages) - 1]
+		}
+
+		for _, conflict := range conflicts {
+			/* Conflict items of an earlier merge of the same item are replaced, so merging again does not add them twice */
+			if existing := merged.ItemById(conflict.Id); existing != nil {
+				merged.ItemStage(existing).RemoveItem(existing)
+			}
+			stage.PlaceItem(conflict, nil, true)
+		}
+	}
+
+	return merged, len(conflicts), nil
+}
+
+
+/* ================================================================================ Private functions */
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+
+func jsonFields(value interface{}) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]json.RawMessage{}
+	err     = json.Unmarshal(data, &fields)
+
+	return fields, err
+}
+
+
+func unmarshalJSONFields(fields map[string]json.RawMessage, target interface{}) error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
+
+
+func unionKeys(maps ...map[string]json.RawMessage) []string {
+	keySet := map[string]bool{}
+	for _, m := range maps {
+		for key := range m {
+			keySet[key] = true
+		}
+	}
+
+	keys := []string{}
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+
+func unionItemIds(maps ...map[string]*mergeItemState) []string {
+	idSet := map[string]bool{}
+	for _, m := range maps {
+		for id := range m {
+			idSet[id] = true
+		}
+	}
+
+	ids := []string{}
+	for id := range idSet {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+
+/* Returns the merged value and whether both sides changed it differently (ours is taken then) */
+func mergeJSONValue(base, ours, theirs json.RawMessage) (json.RawMessage, bool) {
+	switch {
+		case bytes.Equal(ours, theirs):
+			return ours, false
+		case bytes.Equal(ours, base):
+			return theirs, false
+		case bytes.Equal(theirs, base):
+			return ours, false
+		default:
+			return ours, true
+	}
+}
+
+
+func mergeString(base, ours, theirs string) (string, bool) {
+	switch {
+		case ours == theirs:
+			return ours, false
+		case ours == base:
+			return theirs, false
+		case theirs == base:
+			return ours, false
+		default:
+			return ours, true
+	}
+}
+
+
+func mergeItemStates(board *Board) (map[string]*mergeItemState, error) {
+	states := map[string]*mergeItemState{}
+
+	for _, stage := range board.Stages {
+		for _, item := range stage.Items {
+			fields, err := jsonFields(item)
+			if err != nil {
+				return nil, err
+			}
+			delete(fields, "Id")
+
+			states[item.Id] = &mergeItemState{ stage.Title, fields }
+		}
+	}
+
+	return states, nil
+}
+
+
+func mergeItemStateEqual(a, b *mergeItemState) bool {
+	if a.Stage != b.Stage || len(a.Fields) != len(b.Fields) {
+		return false
+	}
+	for key, value := range a.Fields {
+		if !bytes.Equal(value, b.Fields[key]) {
+			return false
+		}
+	}
+	return true
+}
+
+
+func newConflictItem(id string, state *mergeItemState, differences []string) *Item {
+	title := id
+	if value, found := state.Fields["Title"]; found {
+		json.Unmarshal(value, &title)
+	}
+
+	description := fmt.Sprintf("Conflicting changes of item \"%s\" (%s), the version in stage \"%s\" was kept:\n%s", title, id, state.Stage, strings.Join(differences, "\n"))
+
+	item   := NewItem("Conflict: " + title, []Tag{ { "conflict=" + id } }, description, ItemStyle{ color.RGBA{ 255, 255, 255, 255 }, color.RGBA{ 204, 0, 0, 255 } })
+	item.Id = conflictItemId(id)
+	return item
+}
+
+
+/* Conflict items get IDs derived from the conflicting item, so that merging the same versions again gives the same IDs */
+func conflictItemId(id string) string {
+	hash := sha256.Sum256([]byte("conflict\x00" + id))
+	return hex.EncodeToString(hash[:8])
+}
+
+
+func stageTitleIndex(board *Board, title string) int {
+	for i, stage := range board.Stages {
+		if stage.Title == title {
+			return i
+		}
+	}
+	return -1
+}
+
+
+func mergeStageTitles(base, ours, theirs *Board, mergedItems map[string]*mergeItemState) []string {
+	used := map[string]bool{}
+	for _, item := range mergedItems {
+		used[item.Stage] = true
+	}
+
+	keep := func(title string) bool {
+		inBase, inOurs, inTheirs := stageTitleIndex(base, title) >= 0, stageTitleIndex(ours, title) >= 0, stageTitleIndex(theirs, title) >= 0
+
+		if inBase && !(inOurs && inTheirs) {
+			/* Removed on at least one side */
+			return used[title]
+		}
+		return inOurs || inTheirs
+	}
+
+	/* Use the stage order of ours, with stages added by theirs inserted after their predecessor */
+	titles := []string{}
+	for _, stage := range ours.Stages {
+		if keep(stage.Title) {
+			titles = append(titles, stage.Title)
+		}
+	}
+
+	for i, stage := range theirs.Stages {
+		if stageTitleIndex(ours, stage.Title) >= 0 || !keep(stage.Title) {
+			continue
+		}
+
+		position := 0
+		for j := i - 1; j >= 0; j-- {
+			if k := indexOfString(titles, theirs.Stages[j].Title); k >= 0 {
+				position = k + 1
+				break
+			}
+		}
+
+		titles = append(titles, "")
+		copy(titles[position+1:], titles[position:])
+		titles[position] = stage.Title
+	}
+
+	return titles
+}
+
+
+/* Merges the stage settings (e.g. the WIP limit) like the board fields, preferring ours on conflicts */
+func mergeStageFields(stage *Stage, base, ours, theirs *Board) error {
+	stageFields := func(board *Board) (map[string]json.RawMessage, error) {
+		i := stageTitleIndex(board, stage.Title)
+		if i < 0 {
+			return map[string]json.RawMessage{}, nil
+		}
+
+		fields, err := jsonFields(board.Stages[i])
+		delete(fields, "Title")
+		delete(fields, "Items")
+
+		return fields, err
+	}
+
+	baseFields,   err1 := stageFields(base)
+	oursFields,   err2 := stageFields(ours)
+	theirsFields, err3 := stageFields(theirs)
+	if err := firstError(err1, err2, err3); err != nil {
+		return err
+	}
+
+	mergedFields := map[string]json.RawMessage{}
+	for _, key := range unionKeys(baseFields, oursFields, theirsFields) {
+		if value, _ := mergeJSONValue(baseFields[key], oursFields[key], theirsFields[key]); value != nil {
+			mergedFields[key] = value
+		}
+	}
+
+	return unmarshalJSONFields(mergedFields, stage)
+}
+
+
+/* Archived items are merged by ID, as both versions may have archived different items, items active after the merge are dropped */
+func mergeArchives(ours, theirs *Board, mergedItems map[string]*mergeItemState) []*ArchivedItem {
+	archive := []*ArchivedItem{}
+	seenIds := map[string]bool{}
+
+	for _, board := range []*Board{ ours, theirs } {
+		for _, archived := range board.Archive {
+			if archived == nil || archived.Item == nil {
+				continue
+			}
+			if _, active := mergedItems[archived.Item.Id]; active || seenIds[archived.Item.Id] {
+				continue
+			}
+
+			seenIds[archived.Item.Id] = true
+			archive = append(archive, archived)
+		}
+	}
+
+	return archive
+}
+
+
+/* Relations are merged as sets, added on either side are kept and removed on either side are dropped, as well as those of removed items */
+func mergeDependencies(base, ours, theirs *Board, archive []*ArchivedItem, mergedItems map[string]*mergeItemState) []Dependency {
+	existingIds := map[string]bool{}
+	for id := range mergedItems {
+		existingIds[id] = true
+	}
+	for _, archived := range archive {
+		if archived != nil && archived.Item != nil {
+			existingIds[archived.Item.Id] = true
+		}
+	}
+
+	dependencies := []Dependency{}
+	seen         := map[Dependency]bool{}
+	for _, board := range []*Board{ ours, theirs } {
+		for _, dependency := range board.Dependencies {
+			inBase   := base.dependencyIndex(dependency.Blocker, dependency.Blocked) >= 0
+			inOurs   := ours.dependencyIndex(dependency.Blocker, dependency.Blocked) >= 0
+			inTheirs := theirs.dependencyIndex(dependency.Blocker, dependency.Blocked) >= 0
+
+			if (inBase && !(inOurs && inTheirs)) || !existingIds[dependency.Blocker] || !existingIds[dependency.Blocked] {
+				continue
+			}
+			if !seen[dependency] {
+				seen[dependency] = true
+				dependencies     = append(dependencies, dependency)
+			}
+		}
+	}
+
+	return dependencies
+}
+
+
+func stageItemIds(board *Board, title string) []string {
+	ids := []string{}
+	if i := stageTitleIndex(board, title); i >= 0 {
+		for _, item := range board.Stages[i].Items {
+			ids = append(ids, item.Id)
+		}
+	}
+	return ids
+}
+
+
+func indexOfString(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+
+func mergeStageItemOrder(title string, base, ours, theirs *Board, mergedItems map[string]*mergeItemState) []string {
+	baseIds, oursIds, theirsIds := stageItemIds(base, title), stageItemIds(ours, title), stageItemIds(theirs, title)
+
+	/* Take the order of theirs if only theirs reordered the stage, otherwise the one of ours */
+	primaryIds, secondaryIds := oursIds, theirsIds
+	if strings.Join(oursIds, ",") == strings.Join(baseIds, ",") {
+		primaryIds, secondaryIds = theirsIds, oursIds
+	}
+
+	ids := []string{}
+	for _, id := range primaryIds {
+		if item, found := mergedItems[id]; found && item.Stage == title {
+			ids = append(ids, id)
+		}
+	}
+
+	/* Insert items only placed here by the other side after their predecessor there */
+	for i, id := range secondaryIds {
+		if item, found := mergedItems[id]; !found || item.Stage != title || indexOfString(ids, id) >= 0 {
+			continue
+		}
+
+		position := 0
+		for j := i - 1; j >= 0; j-- {
+			if k := indexOfString(ids, secondaryIds[j]); k >= 0 {
+				position = k + 1
+				break
+			}
+		}
+
+		ids = append(ids, "")
+		copy(ids[position+1:], ids[position:])
+		ids[position] = id
+	}
+
+	/* Items moved here in none of both versions (e.g. resolved moves from a removed stage) are appended */
+	for _, id := range unionItemIds(mergedItems) {
+		if mergedItems[id].Stage == title && indexOfString(ids, id) < 0 {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids
+}
\ No newline at end of file
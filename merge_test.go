@@ -0,0 +1,116 @@
+package main
+
+/* Tests of the three-way merge of board versions */
+
+
+/* ================================================================================ Imports */
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"fyne.io/fyne/v2/test"
+)
+
+
+/* ================================================================================ Tests */
+func TestMergeBoards(t *testing.T) {
+	test.NewApp()
+
+	tests := []struct {
+		name      string
+		base      []string
+		ours      []string
+		theirs    []string
+		merged    []string
+		conflicts int
+	}{
+		{ "unchanged",        []string{ "Todo|a=A" },         []string{ "Todo|a=A" },         []string{ "Todo|a=A" },         []string{ "Todo|A" },                            0 },
+		{ "added by ours",    []string{ "Todo|a=A" },         []string{ "Todo|a=A|b=B" },     []string{ "Todo|a=A" },         []string{ "Todo|A|B" },                          0 },
+		{ "added by both",    []string{ "Todo|a=A" },         []string{ "Todo|a=A|b=B" },     []string{ "Todo|a=A|c=C" },     []string{ "Todo|A|C|B" },                        0 },
+		{ "removed",          []string{ "Todo|a=A|b=B" },     []string{ "Todo|a=A|b=B" },     []string{ "Todo|b=B" },         []string{ "Todo|B" },                            0 },
+		{ "changed",          []string{ "Todo|a=A|b=B" },     []string{ "Todo|a=A|b=B2" },    []string{ "Todo|a=A2|b=B" },    []string{ "Todo|A2|B2" },                        0 },
+		{ "moved",            []string{ "Todo|a=A", "Done" }, []string{ "Todo", "Done|a=A" }, []string{ "Todo|a=A", "Done" }, []string{ "Todo", "Done|A" },                    0 },
+		{ "changed by both",  []string{ "Todo|a=A" },         []string{ "Todo|a=A1" },        []string{ "Todo|a=A2" },        []string{ "Todo|A1", "Conflicts|Conflict: A1" }, 1 },
+		{ "removed, changed", []string{ "Todo|a=A" },         []string{ "Todo" },             []string{ "Todo|a=A2" },        []string{ "Todo|A2", "Conflicts|Conflict: A2" }, 1 },
+		{ "without IDs",      []string{ "Todo|=A|=B" },       []string{ "Todo|=A|=B|=C" },    []string{ "Todo|=A|=B" },       []string{ "Todo|A|B|C" },                        0 },
+	}
+
+	for _, tt := range tests {
+		merged, conflicts, err := MergeBoards(newMergeTestBoard(t, tt.base), newMergeTestBoard(t, tt.ours), newMergeTestBoard(t, tt.theirs))
+		if err != nil {
+			t.Errorf("%s: MergeBoards() failed: %s", tt.name, err)
+			continue
+		}
+
+		if stages := mergeTestStages(merged); !reflect.DeepEqual(stages, tt.merged) || conflicts != tt.conflicts {
+			t.Errorf("%s: MergeBoards() = %v, %d conflicts, want %v, %d conflicts", tt.name, stages, conflicts, tt.merged, tt.conflicts)
+		}
+	}
+}
+
+
+/* Merging the result again with the same version (as merge drivers do) keeps a single conflict item per conflict */
+func TestMergeBoardsAgain(t *testing.T) {
+	test.NewApp()
+
+	base, theirs := newMergeTestBoard(t, []string{ "Todo|a=A" }), newMergeTestBoard(t, []string{ "Todo|a=A2" })
+
+	merged, _, err := MergeBoards(base, newMergeTestBoard(t, []string{ "Todo|a=A1" }), theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, conflicts, err := MergeBoards(base, merged, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if stages := mergeTestStages(again); !reflect.DeepEqual(stages, mergeTestStages(merged)) || conflicts != 1 {
+		t.Errorf("MergeBoards() again = %v, %d conflicts, want %v, 1 conflict", stages, conflicts, mergeTestStages(merged))
+	}
+	if first, second := merged.StageByTitle(CONFLICTS_STAGE_TITLE).Items[0].Id, again.StageByTitle(CONFLICTS_STAGE_TITLE).Items[0].Id; first != second {
+		t.Errorf("conflict item IDs %q and %q differ", first, second)
+	}
+}
+
+
+/* ================================================================================ Private functions */
+/* Creates a board from stages given as "Title|ID=Item|ID=Item", loaded from its data like a file, empty IDs are missing like in files of older versions */
+func newMergeTestBoard(t *testing.T, stages []string) *Board {
+	board := NewBoard("Test", nil)
+	for _, stage := range stages {
+		fields := strings.Split(stage, "|")
+		board.AppendStage(fields[0])
+
+		for _, field := range fields[1:] {
+			idAndTitle := strings.SplitN(field, "=", 2)
+			item       := board.Stages[len(board.Stages) - 1].AppendItem(idAndTitle[1], nil, "", DefaultItemStyle)
+			item.Id     = idAndTitle[0]
+
+			/* The versions share their history, so the same items entered their stages at the same time */
+			item.StageEntered = nil
+		}
+	}
+
+	data, err := board.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := NewBoard("", nil)
+	if err := loaded.Load(data); err != nil {
+		t.Fatal(err)
+	}
+	return loaded
+}
+
+
+func mergeTestStages(board *Board) []string {
+	stages := []string{}
+	for _, stage := range board.Stages {
+		titles := []string{ stage.Title }
+		for _, item := range stage.Items {
+			titles = append(titles, item.Title)
+		}
+		stages = append(stages, strings.Join(titles, "|"))
+	}
+	return stages
+}
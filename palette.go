@@ -0,0 +1,297 @@
+package main
+
+/* This file contains the command palette (Ctrl+P), which fuzzy-searches all actions of the menus, tabs, stages, items and filters */
+
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"image/color"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+/* ================================================================================ Public types */
+type Command struct {
+	Title string
+	Run   func()
+}
+
+
+/* ================================================================================ Private types */
+/* Entry passing the navigation keys on to the palette list */
+type paletteEntry struct {
+	widget.Entry
+	onKey func(event *fyne.KeyEvent) bool
+}
+
+
+/* ================================================================================ Public functions */
+/* Collects the commands for the current state from the board, stage and item menus and more, e.g. the item commands only if an item is focused */
+func PaletteCommands() []Command {
+	commands := []Command{}
+	add      := func(prefix string, menuItems []*fyne.MenuItem) {
+		for _, menuItem := range menuItems {
+			if !menuItem.IsSeparator && menuItem.Action != nil {
+				commands = append(commands, Command{ prefix + menuItem.Label, menuItem.Action })
+			}
+		}
+	}
+
+	add("File: ", []*fyne.MenuItem{
+		fyne.NewMenuItem("New Board",      newButtonTapped),
+		fyne.NewMenuItem("Open Board",     loadButtonTapped),
+		fyne.NewMenuItem("Recent Files",   recentButtonTapped),
+		fyne.NewMenuItem("Save Board",     saveButtonTapped),
+		fyne.NewMenuItem("Save Board As",  saveAsButtonTapped),
+		fyne.NewMenuItem("Close Tab",      closeButtonTapped),
+		fyne.NewMenuItem("Next Tab",       func() { selectNeighbourBoardTab(1) }),
+		fyne.NewMenuItem("Previous Tab",   func() { selectNeighbourBoardTab(-1) }),
+	})
+	for _, tab := range boardTabs {
+		selectedTab := tab
+		commands     = append(commands, Command{ "Tab: Switch to " + tab.Title(), func() { selectBoardTab(selectedTab) } })
+	}
+
+	add("Board: ", append([]*fyne.MenuItem{ fyne.NewMenuItem("New Stage", createStageButtonTapped) }, boardMenuItems()...))
+
+	for _, stage := range board.Stages {
+		add(fmt.Sprintf("Stage \"%s\": ", stage.Title), stage.MenuItems())
+	}
+	if item := board.FocusedItem(); item != nil {
+		add(fmt.Sprintf("Item \"%s\": ", item.Title), item.MenuItems())
+	}
+
+	/* The sources are taken now, as the palette takes the focus from the item */
+	if sources := board.ClipboardSourceItems(); len(sources) > 0 {
+		for _, stage := range board.Stages {
+			targetStage := stage
+			commands     = append(commands, Command{ fmt.Sprintf("Move %d Selected Item(s) to Stage \"%s\"", len(sources), stage.Title), func() { moveItemsToStage(sources, targetStage) } })
+		}
+	}
+
+	for _, stage := range board.Stages {
+		for _, item := range stage.Items {
+			targetItem := item
+			commands    = append(commands, Command{ fmt.Sprintf("Go to Item \"%s\" (%s)", item.Title, stage.Title), func() { focusItem(targetItem) } })
+		}
+	}
+
+	for _, expression := range board.SortedTagExpressions() {
+		tag     := Tag{ expression }
+		commands = append(commands, Command{ "Filter: Toggle Tag " + expression, func() { board.ToggleFilterTag(tag) } })
+	}
+	commands = append(commands, Command{ "Filter: Clear", func() { filterBinding.Set("") } })
+	for _, savedFilter := range board.SavedFilters {
+		filter  := savedFilter
+		commands = append(commands, Command{ fmt.Sprintf("Filter: Apply Saved \"%s\" (%s)", filter.Name, filter.Filter), func() { filterBinding.Set(filter.Filter) } })
+		commands = append(commands, Command{ fmt.Sprintf("Filter: Remove Saved \"%s\"", filter.Name), func() { board.RemoveSavedFilter(filter.Name) } })
+	}
+
+	return commands
+}
+
+
+/* Returns whether all characters of the query (ignoring spaces and case) appear in order in the text, and a score preferring consecutive characters, word starts and short texts */
+func FuzzyMatch(query, text string) (int, bool) {
+	queryRunes := []rune(strings.ToLower(strings.ReplaceAll(query, " ", "")))
+	textRunes  := []rune(strings.ToLower(text))
+
+	score    := 0
+	matched  := 0
+	previous := -2
+	for i, r := range textRunes {
+		if matched >= len(queryRunes) {
+			break
+		}
+		if r != queryRunes[matched] {
+			continue
+		}
+
+		score++
+		if i == previous + 1 {
+			score += 3
+		}
+		if i == 0 || (!unicode.IsLetter(textRunes[i - 1]) && !unicode.IsDigit(textRunes[i - 1])) {
+			score += 2
+		}
+		previous = i
+		matched++
+	}
+
+	if matched < len(queryRunes) {
+		return 0, false
+	}
+	return score * 100 - len(textRunes), true
+}
+
+
+/* Returns the matching commands, best matches first, keeping the original order on equal scores */
+func FilterCommands(commands []Command, query string) []Command {
+	if strings.TrimSpace(query) == "" {
+		return commands
+	}
+
+	matches := []Command{}
+	scores  := map[string]int{}
+	for _, command := range commands {
+		if score, ok := FuzzyMatch(query, command.Title); ok {
+			matches               = append(matches, command)
+			scores[command.Title] = score
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return scores[matches[i].Title] > scores[matches[j].Title] })
+
+	return matches
+}
+
+
+func ShowCommandPalette() {
+	commands := PaletteCommands()
+	matches  := commands
+	selected := 0
+
+	var paletteDialog dialog.Dialog
+	run := func(i int) {
+		if i >= 0 && i < len(matches) {
+			paletteDialog.Hide()
+			matches[i].Run()
+		}
+	}
+
+	/* The command highlighted by keyboard is bold, as selecting it in the list would keep it from being tapped */
+	list := widget.NewList(
+		func() int {
+			return len(matches)
+		},
+		func() fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		func(id widget.ListItemID, object fyne.CanvasObject) {
+			label          := object.(*widget.Label)
+			label.TextStyle = fyne.TextStyle{ Bold: id == selected }
+			label.SetText(matches[id].Title)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) {
+		list.UnselectAll()
+		run(id)
+	}
+	highlight := func(i int) {
+		selected = i
+		list.Refresh()
+		list.ScrollTo(i)
+	}
+
+	entry := newPaletteEntry()
+	entry.SetPlaceHolder("Type a command ...")
+	entry.OnChanged = func(text string) {
+		matches = FilterCommands(commands, text)
+		highlight(0)
+	}
+	entry.OnSubmitted = func(string) {
+		run(selected)
+	}
+	entry.onKey = func(event *fyne.KeyEvent) bool {
+		switch event.Name {
+			case fyne.KeyDown:
+				if selected + 1 < len(matches) {
+					highlight(selected + 1)
+				}
+			case fyne.KeyUp:
+				if selected > 0 {
+					highlight(selected - 1)
+				}
+			case fyne.KeyEscape:
+				paletteDialog.Hide()
+			default:
+				return false
+		}
+		return true
+	}
+
+	listSizer := canvas.NewRectangle(color.RGBA{ 0, 0, 0, 0 })
+	listSizer.SetMinSize(fyne.NewSize(600, 300))
+
+	paletteDialog = dialog.NewCustom("Command Palette", "Close", container.NewBorder(entry, nil, nil, nil, container.NewMax(listSizer, list)), window)
+	paletteDialog.Show()
+	entry.OnChanged("")
+	window.Canvas().Focus(entry)
+}
+
+
+/* ================================================================================ Public methods */
+func (w *Board) ShowSaveFilterDialog() {
+	filter, _ := filterBinding.Get()
+	if strings.TrimSpace(filter) == "" {
+		ShowReportDialog("Save Filter", "The current filter is empty.", nil)
+		return
+	}
+
+	ShowEntryDialog("Save Filter \"" + filter + "\"", "Name ...", "",
+		func(text string) {
+			w.RecordUndoStep("Save Filter " + text)
+			w.RemoveSavedFilter(text)
+			w.SavedFilters = append(w.SavedFilters, SavedFilter{ text, filter })
+		},
+	)
+}
+
+
+func (w *Board) RemoveSavedFilter(name string) {
+	for i, savedFilter := range w.SavedFilters {
+		if savedFilter.Name == name {
+			w.RecordUndoStep("Remove Saved Filter " + name)
+			w.SavedFilters = append(w.SavedFilters[:i], w.SavedFilters[i+1:]...)
+			return
+		}
+	}
+}
+
+
+/* ================================================================================ Private methods */
+func (e *paletteEntry) TypedKey(event *fyne.KeyEvent) {
+	if e.onKey != nil && e.onKey(event) {
+		return
+	}
+	e.Entry.TypedKey(event)
+}
+
+
+/* ================================================================================ Private functions */
+func newPaletteEntry() *paletteEntry {
+	entry := &paletteEntry{}
+	entry.ExtendBaseWidget(entry)
+
+	return entry
+}
+
+
+func moveItemsToStage(items []*Item, stage *Stage) {
+	if len(items) == 1 {
+		items[0].MoveTo(stage, nil, true)
+		return
+	}
+
+	if errors := board.MoveItems(items, stage); len(errors) > 0 {
+		ShowReportDialog("Move Items", "The following items could not be moved:", errors)
+	}
+}
+
+
+/* Focuses the item, clearing the filter if it hides the item */
+func focusItem(item *Item) {
+	if !item.Visible() {
+		/* The binding notifies asynchronously, but the item must be visible to take the focus */
+		board.SetTagFilter("")
+		filterBinding.Set("")
+	}
+	window.Canvas().Focus(item)
+}
@@ -0,0 +1,209 @@
+package main
+
+/* This file contains the automation rules of stages, which change items entering a stage and guard items leaving it */
+
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"image/color"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"fyne.io/fyne/v2/theme"
+)
+
+
+/* ================================================================================ Public types */
+/* Rule tags without value (e.g. "reviewed") also match tags with that key (e.g. "reviewed=alice") */
+type StageRules struct {
+	EnterAddTags        []Tag      `json:",omitempty"`
+	EnterRemoveTags     []Tag      `json:",omitempty"`
+	EnterStyle          *ItemStyle `json:",omitempty"`
+	EnterDateTagKey     string     `json:",omitempty"`
+	EnterResetChecklist bool       `json:",omitempty"`
+	LeaveRequiredTags   []Tag      `json:",omitempty"`
+}
+
+
+/* ================================================================================ Private variables */
+var checkedChecklistLinePattern = regexp.MustCompile(`(?m)^(\s*(?:[-*]\s+)?)\[[xX]\]`)
+
+
+/* ================================================================================ Public methods */
+/* Returns an error naming the required tags the item lacks to leave the stage */
+func (r *StageRules) CheckLeave(item *Item, stage *Stage) error {
+	if r == nil {
+		return nil
+	}
+
+	missing := []string{}
+	for _, required := range r.LeaveRequiredTags {
+		if itemTagIndex(item, required) < 0 {
+			missing = append(missing, required.Expression)
+		}
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("Item \"%s\" needs the tag(s) \"%s\" to leave stage \"%s\"", item.Title, strings.Join(missing, "\", \""), stage.Title)
+	}
+	return nil
+}
+
+
+func (r *StageRules) ApplyEnter(item *Item) {
+	if r == nil {
+		return
+	}
+
+	/* The tags are rebuilt instead of changed in place, as their array may be shared, e.g. with copies of the item */
+	tags := withoutRuleTags(item.Tags, r.EnterRemoveTags)
+
+	for _, tag := range r.EnterAddTags {
+		if ruleTagIndex(tags, tag) < 0 {
+			tags = append(tags, tag)
+		}
+	}
+
+	if r.EnterDateTagKey != "" {
+		tags = append(withoutRuleTags(tags, []Tag{ { r.EnterDateTagKey } }), Tag{ r.EnterDateTagKey + "=" + time.Now().Format("2006-01-02") })
+	}
+	item.Tags = tags
+
+	if r.EnterStyle != nil {
+		item.Style = *r.EnterStyle
+	}
+
+	/* Checklists are description lines starting with "[x]" or "[ ]", e.g. imported from Trello */
+	if r.EnterResetChecklist {
+		item.Description = checkedChecklistLinePattern.ReplaceAllString(item.Description, "$1[ ]")
+	}
+}
+
+
+func (w *Stage) ShowEditRulesDialog() {
+	rules := w.Rules
+	if rules == nil {
+		rules = &StageRules{}
+	}
+
+	addTagsEntry := widget.NewEntry()
+	addTagsEntry.SetPlaceHolder("Tag1=Value1; Tag2; ...")
+	addTagsEntry.SetText(ComposeTagEditString(rules.EnterAddTags))
+	removeTagsEntry := widget.NewEntry()
+	removeTagsEntry.SetPlaceHolder("Tag1=Value1; Tag2; ...")
+	removeTagsEntry.SetText(ComposeTagEditString(rules.EnterRemoveTags))
+	dateTagKeyEntry := widget.NewEntry()
+	dateTagKeyEntry.SetPlaceHolder("Tag key, e.g. done ...")
+	dateTagKeyEntry.SetText(rules.EnterDateTagKey)
+	resetChecklistCheck := widget.NewCheck("", nil)
+	resetChecklistCheck.SetChecked(rules.EnterResetChecklist)
+	requiredTagsEntry := widget.NewEntry()
+	requiredTagsEntry.SetPlaceHolder("Tag1=Value1; Tag2; ...")
+	requiredTagsEntry.SetText(ComposeTagEditString(rules.LeaveRequiredTags))
+
+	style := DefaultItemStyle
+	if rules.EnterStyle != nil {
+		style = *rules.EnterStyle
+	}
+	styleCheck := widget.NewCheck("", nil)
+	styleCheck.SetChecked(rules.EnterStyle != nil)
+	foregroundColorButton := widget.NewButtonWithIcon("Foreground", theme.ColorPaletteIcon(),
+		func() {
+			ShowColorPickerDialog("Choose Foreground Color", "Please choose the color for item text and tag frames.", style.Foreground,
+				func(selected color.RGBA) {
+					style.Foreground = selected
+					styleCheck.SetChecked(true)
+				},
+			)
+		},
+	)
+	backgroundColorButton := widget.NewButtonWithIcon("Background", theme.ColorPaletteIcon(),
+		func() {
+			ShowColorPickerDialog("Choose Background Color", "Please choose the color for the item's background.", style.Background,
+				func(selected color.RGBA) {
+					style.Background = selected
+					styleCheck.SetChecked(true)
+				},
+			)
+		},
+	)
+
+	form := widget.NewForm(
+		widget.NewFormItem("On Enter: Add Tags",        addTagsEntry),
+		widget.NewFormItem("On Enter: Remove Tags",     removeTagsEntry),
+		widget.NewFormItem("On Enter: Stamp Date Tag",  dateTagKeyEntry),
+		widget.NewFormItem("On Enter: Set Colors",      container.NewBorder(nil, nil, styleCheck, nil, container.NewGridWithColumns(2, foregroundColorButton, backgroundColorButton))),
+		widget.NewFormItem("On Enter: Reset Checklist", resetChecklistCheck),
+		widget.NewFormItem("On Leave: Require Tags",    requiredTagsEntry),
+	)
+
+	dialog.ShowCustomConfirm("Rules of Stage \"" + w.Title + "\"", "OK", "Cancel", form,
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			newRules := &StageRules{
+				EnterAddTags:        ParseTagEditString(addTagsEntry.Text),
+				EnterRemoveTags:     ParseTagEditString(removeTagsEntry.Text),
+				EnterDateTagKey:     strings.TrimSpace(dateTagKeyEntry.Text),
+				EnterResetChecklist: resetChecklistCheck.Checked,
+				LeaveRequiredTags:   ParseTagEditString(requiredTagsEntry.Text),
+			}
+			if styleCheck.Checked {
+				newRules.EnterStyle = &style
+			}
+
+			/* Do not store empty rules, keeping the board file clean */
+			if len(newRules.EnterAddTags) < 1 && len(newRules.EnterRemoveTags) < 1 && newRules.EnterDateTagKey == "" && newRules.EnterStyle == nil && !newRules.EnterResetChecklist && len(newRules.LeaveRequiredTags) < 1 {
+				newRules = nil
+			}
+
+			board.RecordUndoStep("Edit Rules of " + w.Title)
+			w.Rules = newRules
+		}, window,
+	)
+}
+
+
+/* ================================================================================ Private functions */
+/* Returns the index of the first item tag matching the rule tag, or -1 */
+func itemTagIndex(item *Item, ruleTag Tag) int {
+	return ruleTagIndex(item.Tags, ruleTag)
+}
+
+
+/* Rule tags without value match all values of their key */
+func ruleTagIndex(tags []Tag, ruleTag Tag) int {
+	ruleKey, _, ruleHasValue := ruleTag.KeyValue()
+
+	for i, tag := range tags {
+		if tag.Expression == ruleTag.Expression {
+			return i
+		}
+		if key, _, found := tag.KeyValue(); !ruleHasValue && found && key == ruleKey {
+			return i
+		}
+	}
+	return -1
+}
+
+
+/* Returns a new slice of the tags not matching any of the rule tags */
+func withoutRuleTags(tags []Tag, ruleTags []Tag) []Tag {
+	kept := []Tag(nil)
+	for _, tag := range tags {
+		matched := false
+		for _, ruleTag := range ruleTags {
+			matched = matched || ruleTagIndex([]Tag{ tag }, ruleTag) >= 0
+		}
+		if !matched {
+			kept = append(kept, tag)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,261 @@
+package main
+
+/* This file contains the multi-selection of items (Ctrl/Shift+click, across stages) and the bulk operations on selected items, each undoable as one step */
+
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"image/color"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+
+/* ================================================================================ Public methods */
+/* Returns the selected items in board order (stage by stage, top to bottom) */
+func (w *Board) SelectedItems() []*Item {
+	items := []*Item{}
+	for _, stage := range w.Stages {
+		for _, item := range stage.Items {
+			if item.selected {
+				items = append(items, item)
+			}
+		}
+	}
+	return items
+}
+
+
+func (w *Board) ToggleItemSelected(item *Item) {
+	item.SetSelected(!item.selected)
+	w.selectionAnchor = item
+}
+
+
+/* Selects all visible items between the last toggled item and the given one in board order */
+func (w *Board) SelectItemRange(item *Item) {
+	if w.selectionAnchor == nil || w.ItemStage(w.selectionAnchor) == nil {
+		w.ToggleItemSelected(item)
+		return
+	}
+
+	inRange := false
+	for _, stage := range w.Stages {
+		for _, current := range stage.Items {
+			boundary := current == item || current == w.selectionAnchor
+			if boundary || inRange {
+				if current.Visible() {
+					current.SetSelected(true)
+				}
+			}
+			if boundary && item != w.selectionAnchor {
+				inRange = !inRange
+			}
+		}
+	}
+}
+
+
+func (w *Board) ClearSelection() {
+	for _, item := range w.SelectedItems() {
+		item.SetSelected(false)
+	}
+	w.selectionAnchor = nil
+}
+
+
+/* Moves the items to the end of the stage, returning the errors of items not allowed to move by the stage rules */
+func (w *Board) MoveItems(items []*Item, targetStage *Stage) []string {
+	w.RecordUndoStep(fmt.Sprintf("Move %d Items", len(items)))
+
+	errors := []string{}
+	for _, item := range items {
+		if w.ItemStage(item) == targetStage {
+			continue
+		}
+		if err := w.MoveItem(item, targetStage, nil, true); err != nil {
+			errors = append(errors, err.Error())
+		}
+	}
+	return errors
+}
+
+
+func (w *Board) AddItemsTags(items []*Item, tags []Tag) {
+	w.RecordUndoStep(fmt.Sprintf("Add Tags to %d Items", len(items)))
+
+	for _, item := range items {
+		for _, tag := range tags {
+			if itemTagIndex(item, tag) < 0 {
+				item.Tags = append(item.Tags, tag)
+			}
+		}
+		item.Refresh()
+	}
+	w.ApplyTagFilter()
+}
+
+
+/* Tags without value (e.g. "prio") also remove tags with that key (e.g. "prio=1") */
+func (w *Board) RemoveItemsTags(items []*Item, tags []Tag) {
+	w.RecordUndoStep(fmt.Sprintf("Remove Tags from %d Items", len(items)))
+
+	for _, item := range items {
+		for _, tag := range tags {
+			for i := itemTagIndex(item, tag); i >= 0; i = itemTagIndex(item, tag) {
+				item.Tags = append(item.Tags[:i], item.Tags[i+1:]...)
+			}
+		}
+		item.Refresh()
+	}
+	w.ApplyTagFilter()
+}
+
+
+func (w *Board) SetItemsForeground(items []*Item, foreground color.RGBA) {
+	w.RecordUndoStep(fmt.Sprintf("Set Foreground of %d Items", len(items)))
+
+	for _, item := range items {
+		item.Style.Foreground = foreground
+		item.Refresh()
+	}
+}
+
+
+func (w *Board) SetItemsBackground(items []*Item, background color.RGBA) {
+	w.RecordUndoStep(fmt.Sprintf("Set Background of %d Items", len(items)))
+
+	for _, item := range items {
+		item.Style.Background = background
+		item.Refresh()
+	}
+}
+
+
+func (w *Board) ArchiveItems(items []*Item) {
+	w.RecordUndoStep(fmt.Sprintf("Archive %d Items", len(items)))
+
+	for _, item := range items {
+		item.SetSelected(false)
+		w.ArchiveItem(item)
+	}
+}
+
+
+func (w *Board) RemoveItems(items []*Item) {
+	w.RecordUndoStep(fmt.Sprintf("Remove %d Items", len(items)))
+
+	for _, item := range items {
+		w.RemoveItem(item)
+	}
+}
+
+
+func (w *Board) ShowBulkActionsDialog() {
+	items := w.SelectedItems()
+	if len(items) < 1 {
+		ShowReportDialog("Selected Items", "No items are selected.\n\nSelect items with Ctrl+click (toggle) or Shift+click (range) on their titles.", nil)
+		return
+	}
+
+	var bulkDialog dialog.Dialog
+	apply := func(action func()) {
+		bulkDialog.Hide()
+		action()
+	}
+
+	stageTitles := make([]string, len(w.Stages))
+	for i, stage := range w.Stages {
+		stageTitles[i] = stage.Title
+	}
+	stageSelect := widget.NewSelect(stageTitles, nil)
+	stageSelect.PlaceHolder = "Stage ..."
+	moveButton := widget.NewButtonWithIcon("Move", theme.NavigateNextIcon(),
+		func() {
+			i := stageSelect.SelectedIndex()
+			if i < 0 {
+				return
+			}
+
+			apply(func() {
+				if errors := w.MoveItems(items, w.Stages[i]); len(errors) > 0 {
+					ShowReportDialog("Move Items", "The following items could not be moved:", errors)
+				}
+			})
+		},
+	)
+
+	tagsEntry := widget.NewEntry()
+	tagsEntry.SetPlaceHolder("Tag1=Value1; Tag2; ...")
+	addTagsButton    := widget.NewButtonWithIcon("Add", theme.ContentAddIcon(), func() { apply(func() { w.AddItemsTags(items, ParseTagEditString(tagsEntry.Text)) }) })
+	removeTagsButton := widget.NewButtonWithIcon("Remove", theme.ContentRemoveIcon(), func() { apply(func() { w.RemoveItemsTags(items, ParseTagEditString(tagsEntry.Text)) }) })
+
+	foregroundColorButton := widget.NewButtonWithIcon("Foreground", theme.ColorPaletteIcon(),
+		func() {
+			apply(func() {
+				ShowColorPickerDialog("Choose Foreground Color", "Please choose the color for item text and tag frames.", items[0].Style.Foreground,
+					func(selected color.RGBA) {
+						w.SetItemsForeground(items, selected)
+					},
+				)
+			})
+		},
+	)
+	backgroundColorButton := widget.NewButtonWithIcon("Background", theme.ColorPaletteIcon(),
+		func() {
+			apply(func() {
+				ShowColorPickerDialog("Choose Background Color", "Please choose the color for the item's background.", items[0].Style.Background,
+					func(selected color.RGBA) {
+						w.SetItemsBackground(items, selected)
+					},
+				)
+			})
+		},
+	)
+
+	archiveButton := widget.NewButtonWithIcon("Archive", theme.DownloadIcon(), func() { apply(func() { w.ArchiveItems(items) }) })
+	removeButton  := widget.NewButtonWithIcon("Remove", theme.DeleteIcon(),
+		func() {
+			apply(func() {
+				ShowConfirmDialog("Remove Items", fmt.Sprintf("This will remove %d items from the board.\n\nAre you sure?\n", len(items)), func() { w.RemoveItems(items) })
+			})
+		},
+	)
+	clearButton := widget.NewButtonWithIcon("Clear Selection", theme.CancelIcon(), func() { apply(w.ClearSelection) })
+
+	form := widget.NewForm(
+		widget.NewFormItem("Move to Stage", container.NewBorder(nil, nil, nil, moveButton, stageSelect)),
+		widget.NewFormItem("Tags",          container.NewBorder(nil, nil, nil, container.NewHBox(addTagsButton, removeTagsButton), tagsEntry)),
+		widget.NewFormItem("Colors",        container.NewGridWithColumns(2, foregroundColorButton, backgroundColorButton)),
+		widget.NewFormItem("Items",         container.NewGridWithColumns(3, archiveButton, removeButton, clearButton)),
+	)
+
+	bulkDialog = dialog.NewCustom(fmt.Sprintf("%d Selected Items", len(items)), "Close", form, window)
+	bulkDialog.Show()
+}
+
+
+func (w *Item) SetSelected(selected bool) {
+	if w.selected != selected {
+		w.selected = selected
+		w.Refresh()
+	}
+}
+
+
+/* Plain taps expand/collapse the item, Ctrl+tap toggles its selection and Shift+tap selects a range */
+func (w *Item) TitleTapped(modifier desktop.Modifier) {
+	switch {
+		case modifier & desktop.ControlModifier != 0 || modifier & desktop.SuperModifier != 0:
+			board.ToggleItemSelected(w)
+		case modifier & desktop.ShiftModifier != 0:
+			board.SelectItemRange(w)
+		default:
+			w.ToggleExpanded()
+	}
+}
+
@@ -0,0 +1,183 @@
+package main
+
+/* This file contains the export of a rendered snapshot of the whole board (including items scrolled out of view) into PNG or PDF files */
+
+
+/* ================================================================================ Imports */
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/png"
+	"image/color"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/software"
+	"fyne.io/fyne/v2/theme"
+)
+
+
+/* ================================================================================ Constants */
+const (
+	SNAPSHOT_MIN_WIDTH = 800
+	PDF_PAGE_WIDTH     = 842 /* A4 landscape in points */
+	PDF_PAGE_HEIGHT    = 595
+	PDF_PAGE_MARGIN    = 20
+)
+
+
+/* ================================================================================ Private types */
+type pdfPage struct {
+	content, image []byte
+}
+
+
+/* ================================================================================ Public functions */
+func RenderBoardSnapshot(board *Board) image.Image {
+	nameLabel := NewCustomLabel(fyne.TextAlignCenter, PaintStyle{ color.RGBA{ 255, 255, 255, 255 }, color.RGBA{ 0, 0, 0, 0 }, color.RGBA{ 0, 0, 0, 0 }, 0 }, false, board.Name, theme.TextSubHeadingSize(), fyne.TextStyle{}, Paddings{ 1.0, 1.0, 1.0, 1.0 }, Paddings{ 0.0, 0.0, 0.0, 0.0 })
+	stageGrid := container.NewGridWithColumns(len(board.Stages))
+	if len(board.Stages) < 1 {
+		stageGrid = container.NewGridWithColumns(1)
+	}
+
+	for _, stage := range board.Stages {
+		titleLabel     := NewCustomLabel(fyne.TextAlignLeading, PaintStyle{ color.RGBA{ 255, 255, 255, 255 }, color.RGBA{ 0, 0, 0, 0 }, color.RGBA{ 0, 0, 0, 0 }, 0 }, false, stage.Title, theme.TextSubHeadingSize(), fyne.TextStyle{ Italic: true }, Paddings{ 1.0, 1.0, 1.0, 1.0 }, Paddings{ 0.0, 0.0, 0.0, 0.0 })
+		stageContainer := container.NewVBox(titleLabel)
+
+		/* Render copies of the items, so the widgets of the board shown in the window are not moved to another canvas */
+		for _, item := range stage.Items {
+			snapshotItem         := NewItem(item.Title, item.Tags, item.Description, item.Style)
+			snapshotItem.Expanded = item.Expanded
+			snapshotItem.SetFilterTags(board.FilterTags)
+
+			stageContainer.Add(snapshotItem)
+		}
+
+		stageGrid.Add(stageContainer)
+	}
+
+	content := container.NewBorder(nameLabel, nil, nil, nil, stageGrid)
+	width   := fyne.Max(SNAPSHOT_MIN_WIDTH, board.Size().Width)
+
+	snapshotCanvas := software.NewCanvas()
+	snapshotCanvas.SetContent(content)
+
+	/* The item heights depend on their width due to line wrapping, so layout twice to get the final height */
+	snapshotCanvas.Resize(fyne.NewSize(width, content.MinSize().Height))
+	snapshotCanvas.Resize(fyne.NewSize(width, content.MinSize().Height + theme.Padding() * 2))
+
+	return snapshotCanvas.Capture()
+}
+
+
+func ExportBoardPNG(board *Board) ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	if err := png.Encode(buffer, RenderBoardSnapshot(board)); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+
+func ExportBoardPDF(board *Board) ([]byte, error) {
+	snapshot := RenderBoardSnapshot(board)
+	bounds   := snapshot.Bounds()
+
+	/* Scale the snapshot to the page width and split it into slices of the page height */
+	scale       := float64(PDF_PAGE_WIDTH - 2 * PDF_PAGE_MARGIN) / float64(bounds.Dx())
+	sliceHeight := int(float64(PDF_PAGE_HEIGHT - 2 * PDF_PAGE_MARGIN) / scale)
+	pages       := []pdfPage{}
+
+	for top := bounds.Min.Y; top < bounds.Max.Y; top += sliceHeight {
+		bottom := top + sliceHeight
+		if bottom > bounds.Max.Y {
+			bottom = bounds.Max.Y
+		}
+
+		page, err := composePDFImagePage(snapshot, image.Rect(bounds.Min.X, top, bounds.Max.X, bottom), scale)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, page)
+	}
+
+	return composePDF(pages), nil
+}
+
+
+/* ================================================================================ Private functions */
+func composePDFImagePage(snapshot image.Image, slice image.Rectangle, scale float64) (pdfPage, error) {
+	pixels := &bytes.Buffer{}
+	writer := zlib.NewWriter(pixels)
+
+	for y := slice.Min.Y; y < slice.Max.Y; y++ {
+		for x := slice.Min.X; x < slice.Max.X; x++ {
+			c := ColorToRGBA(snapshot.At(x, y))
+			if _, err := writer.Write([]byte{ c.R, c.G, c.B }); err != nil {
+				return pdfPage{}, err
+			}
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return pdfPage{}, err
+	}
+
+	width  := float64(slice.Dx()) * scale
+	height := float64(slice.Dy()) * scale
+
+	/* Draw the image at the top of the page, scaled to its final size */
+	content      := fmt.Sprintf("q %.2f 0 0 %.2f %d %.2f cm /Snapshot Do Q", width, height, PDF_PAGE_MARGIN, float64(PDF_PAGE_HEIGHT - PDF_PAGE_MARGIN) - height)
+	contentBytes := []byte(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+
+	imageBuffer := &bytes.Buffer{}
+	fmt.Fprintf(imageBuffer, "<< /Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode /Length %d >>\nstream\n", slice.Dx(), slice.Dy(), pixels.Len())
+	imageBuffer.Write(pixels.Bytes())
+	imageBuffer.WriteString("\nendstream")
+
+	return pdfPage{ contentBytes, imageBuffer.Bytes() }, nil
+}
+
+
+func composePDF(pages []pdfPage) []byte {
+	buffer  := &bytes.Buffer{}
+	offsets := []int{}
+
+	writeObject := func(body []byte) int {
+		offsets = append(offsets, buffer.Len())
+		fmt.Fprintf(buffer, "%d 0 obj\n", len(offsets))
+		buffer.Write(body)
+		buffer.WriteString("\nendobj\n")
+		return len(offsets)
+	}
+
+	buffer.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	/* Objects 1 and 2 are the catalog and the page tree, each page takes three objects (page, content, image) */
+	kids := &bytes.Buffer{}
+	for i := range pages {
+		fmt.Fprintf(kids, "%d 0 R ", 3 + i * 3)
+	}
+
+	writeObject([]byte("<< /Type /Catalog /Pages 2 0 R >>"))
+	writeObject([]byte(fmt.Sprintf("<< /Type /Pages /Kids [ %s] /Count %d >>", kids.String(), len(pages))))
+
+	for _, page := range pages {
+		pageId := len(offsets) + 1
+
+		writeObject([]byte(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [ 0 0 %d %d ] /Contents %d 0 R /Resources << /XObject << /Snapshot %d 0 R >> >> >>", PDF_PAGE_WIDTH, PDF_PAGE_HEIGHT, pageId + 1, pageId + 2)))
+		writeObject(page.content)
+		writeObject(page.image)
+	}
+
+	xrefOffset := buffer.Len()
+	fmt.Fprintf(buffer, "xref\n0 %d\n0000000000 65535 f \n", len(offsets) + 1)
+	for _, offset := range offsets {
+		fmt.Fprintf(buffer, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(buffer, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets) + 1, xrefOffset)
+
+	return buffer.Bytes()
+}
\ No newline at end of file
@@ -5,6 +5,9 @@ package main
 
 /* ================================================================================ Imports */
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"image/color"
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/widget"
@@ -16,8 +19,10 @@ import (
 /* ================================================================================ Public types */
 type Stage struct {
 	widget.BaseWidget `json:"-"`
-	Title  string
-	Items  []*Item
+	Title    string
+	WipLimit int         `json:",omitempty"`
+	Rules    *StageRules `json:",omitempty"`
+	Items    []*Item
 }
 
 
@@ -65,27 +70,40 @@ func (w *Stage) ItemAtPosition(position fyne.Position) *Item {
 }
 
 
-func (w *Stage) AppendItem(title string, tags []Tag, description string, style ItemStyle) {
+func (w *Stage) AppendItem(title string, tags []Tag, description string, style ItemStyle) *Item {
 	item := NewItem(title, tags, description, style)
 
-	w.Items = append(w.Items, item)
-	w.Refresh()
+	w.PlaceItem(item, nil, true)
+
+	return item
 }
 
 
 func (w *Stage) InsertItem(after bool, reference *Item, title string, tags []Tag, description string, style ItemStyle) bool {
-	i := w.ItemIndex(reference)
-	if i < 0 {
+	if w.ItemIndex(reference) < 0 {
 		return false
 	}
-	if after {
-		i++
+
+	return w.PlaceItem(NewItem(title, tags, description, style), reference, after)
+}
+
+
+func (w *Stage) PlaceItem(item *Item, reference *Item, after bool) bool {
+	i := len(w.Items)
+
+	if reference != nil {
+		i = w.ItemIndex(reference)
+		if i < 0 {
+			return false
+		}
+		if after {
+			i++
+		}
 	}
 
 	w.Items = append(w.Items, nil)
 	copy(w.Items[i+1:], w.Items[i:])
 
-	item := NewItem(title, tags, description, style)
 	w.Items[i] = item
 	w.Refresh()
 
@@ -106,10 +124,23 @@ func (w *Stage) RemoveItem(toRemove *Item) bool {
 }
 
 
+/* Returns the title with the item count and WIP limit if set, and whether the limit is exceeded */
+func (w *Stage) DisplayTitle() (string, bool) {
+	if w.WipLimit < 1 {
+		return w.Title, false
+	}
+
+	return fmt.Sprintf("%s (%d/%d)", w.Title, len(w.Items), w.WipLimit), len(w.Items) > w.WipLimit
+}
+
+
 func (w *Stage) ShowCreateItemDialog() {
-	ShowItemDialog("New", "", "", "", ItemStyle{ color.RGBA{ 0, 0, 0, 255 }, color.RGBA{ 255, 255, 153, 255 } },
+	ShowItemDialog("New", "", "", "", DefaultItemStyle, board.ItemTemplates, board.VocabularyTags(), board.TagCounts(),
 		func(title, tagEditString, description string, style ItemStyle) {
-			w.AppendItem(title, ParseTagEditString(tagEditString), description, style)
+			board.RecordUndoStep("New Item " + title)
+			item := w.AppendItem(title, ParseTagEditString(tagEditString), description, style)
+			w.Rules.ApplyEnter(item)
+			item.Refresh()
 		},
 	)
 }
@@ -118,6 +149,7 @@ func (w *Stage) ShowCreateItemDialog() {
 func (w *Stage) ShowEditStageTitleDialog() {
 	ShowEntryDialog("Edit Stage Title", "Title ...", w.Title,
 		func(text string) {
+			board.RecordUndoStep("Edit Stage Title " + w.Title)
 			w.Title = text
 			w.Refresh()
 		},
@@ -125,6 +157,27 @@ func (w *Stage) ShowEditStageTitleDialog() {
 }
 
 
+func (w *Stage) ShowEditWipLimitDialog() {
+	limitText := ""
+	if w.WipLimit > 0 {
+		limitText = strconv.Itoa(w.WipLimit)
+	}
+
+	ShowEntryDialog("Edit WIP Limit (empty for none)", "Maximum item count ...", limitText,
+		func(text string) {
+			limit, err := strconv.Atoi(strings.TrimSpace(text))
+			if err != nil || limit < 0 {
+				limit = 0
+			}
+
+			board.RecordUndoStep("Edit WIP Limit of " + w.Title)
+			w.WipLimit = limit
+			w.Refresh()
+		},
+	)
+}
+
+
 func (w *Stage) ShowRemoveStageConfirmDialog() {
 	ShowConfirmDialog("Remove Stage", "This will remove the stage and all contained items from the board.\n\nAre you sure?\n",
 		func() {
@@ -134,13 +187,20 @@ func (w *Stage) ShowRemoveStageConfirmDialog() {
 }
 
 
+func (w *Stage) MenuItems() []*fyne.MenuItem {
+	return []*fyne.MenuItem{
+		fyne.NewMenuItem("New Item",         w.ShowCreateItemDialog),
+		fyne.NewMenuItem("Edit Stage Title", w.ShowEditStageTitleDialog),
+		fyne.NewMenuItem("Edit WIP Limit",   w.ShowEditWipLimitDialog),
+		fyne.NewMenuItem("Edit Rules",       w.ShowEditRulesDialog),
+		fyne.NewMenuItem("Paste Items",      w.PasteItems),
+		fyne.NewMenuItem("Remove Stage",     w.ShowRemoveStageConfirmDialog),
+	}
+}
+
+
 func (w *Stage) ShowStageMenu() {
-	menu := widget.NewPopUpMenu(
-		fyne.NewMenu("Stage", 
-			fyne.NewMenuItem("Edit Stage Title", w.ShowEditStageTitleDialog),
-			fyne.NewMenuItem("Remove Stage",     w.ShowRemoveStageConfirmDialog),
-		), window.Canvas(),
-	)
+	menu := widget.NewPopUpMenu(fyne.NewMenu("Stage", w.MenuItems()...), window.Canvas())
 	menu.ShowAtPosition(fyne.NewPos(w.Position().X + w.Size().Width - menu.Size().Width - 30, w.Position().Y + menu.Size().Height + 10))
 }
 
@@ -156,7 +216,7 @@ func (w *Stage) SetFilterTags(filterTags []Tag) {
 func (w *Stage) CreateRenderer() fyne.WidgetRenderer {
 	w.ExtendBaseWidget(w)
 
-	titleLabel := NewCustomLabel(fyne.TextAlignLeading, PaintStyle{ color.RGBA{ 255, 255, 255, 255 }, color.RGBA{ 0, 0, 0, 0 }, color.RGBA{ 0, 0, 0, 0 }, 0 }, false, w.Title, theme.TextSubHeadingSize(), fyne.TextStyle{ Italic: true }, Paddings{ 1.0, 1.0, 1.0, 1.0 }, Paddings{ 0.0, 0.0, 0.0, 0.0 })
+	titleLabel := NewCustomLabel(fyne.TextAlignLeading, PaintStyle{ color.RGBA{ 255, 255, 255, 255 }, color.RGBA{ 0, 0, 0, 0 }, color.RGBA{ 0, 0, 0, 0 }, 0 }, false, "", theme.TextSubHeadingSize(), fyne.TextStyle{ Italic: true }, Paddings{ 1.0, 1.0, 1.0, 1.0 }, Paddings{ 0.0, 0.0, 0.0, 0.0 })
 	toolbar    := widget.NewToolbar(
 		widget.NewToolbarAction(theme.ContentAddIcon(), w.ShowCreateItemDialog),
 		widget.NewToolbarAction(theme.MoreVerticalIcon(), w.ShowStageMenu),
@@ -169,7 +229,10 @@ func (w *Stage) CreateRenderer() fyne.WidgetRenderer {
 
 	scrollArea := container.NewVScroll(itemContainer)
 
-	return &stageRenderer{ titleLabel, toolbar, scrollArea, itemContainer, widget.NewSeparator(), widget.NewSeparator(), w }
+	renderer := &stageRenderer{ titleLabel, toolbar, scrollArea, itemContainer, widget.NewSeparator(), widget.NewSeparator(), w }
+	renderer.syncTitleLabel()
+
+	return renderer
 }
 
 
@@ -210,7 +273,7 @@ func (r stageRenderer) MinSize() fyne.Size {
 
 
 func (r stageRenderer) Refresh() {
-	r.titleLabel.Text = r.w.Title
+	r.syncTitleLabel()
 	r.titleLabel.Refresh()
 
 	for _, item := range r.itemContainer.Objects {
@@ -230,4 +293,17 @@ func (r stageRenderer) Objects() []fyne.CanvasObject {
 
 
 func (r stageRenderer) Destroy() {
-}
\ No newline at end of file
+}
+
+
+/* ================================================================================ Private rendering methods */
+func (r stageRenderer) syncTitleLabel() {
+	title, limitExceeded := r.w.DisplayTitle()
+
+	r.titleLabel.Text = title
+	if limitExceeded {
+		r.titleLabel.Style.Foreground = color.RGBA{ 255, 102, 102, 255 }
+	} else {
+		r.titleLabel.Style.Foreground = color.RGBA{ 255, 255, 255, 255 }
+	}
+}
@@ -10,7 +10,14 @@ import (
 )
 
 
+/* ================================================================================ Constants */
+const (
+	TAG_PATH_SEPARATOR = "/"
+)
+
+
 /* ================================================================================ Public types */
+/* Statements can be hierarchical, e.g. "project/backend/api" is a descendant of "project/backend" and "project" */
 type Tag struct {
 	Expression string
 }
@@ -57,12 +64,67 @@ func ComposeTagEditString(tags []Tag) string {
 
 
 /* ================================================================================ Public methods */
+func (t *Tag) KeyValue() (key, value string, found bool) {
+	key, value, found = strings.Cut(t.Expression, "=")
+	return strings.TrimSpace(key), strings.TrimSpace(value), found
+}
+
+
+/* Hierarchical statements are shortened to the initials of their ancestors, e.g. "p/b/api" */
 func (t *Tag) DisplayString() string {
 	before, after, found := strings.Cut(t.Expression, "=")
 
 	if(found) {
 		return fmt.Sprintf("%s: %s", before, after)
 	} else {
-		return before
+		segments := strings.Split(before, TAG_PATH_SEPARATOR)
+		for i, segment := range segments[:len(segments) - 1] {
+			if runes := []rune(strings.TrimSpace(segment)); len(runes) > 0 {
+				segments[i] = string(runes[0])
+			}
+		}
+		return strings.Join(segments, TAG_PATH_SEPARATOR)
 	}
-}
\ No newline at end of file
+}
+
+
+/* Returns the path of ancestors and the statement itself, e.g. "project", "project/backend", "project/backend/api", or nil for key=value expressions (and ones without any segment), built from the non-empty segments only (e.g. "project//api" is treated as "project/api") */
+func (t *Tag) Paths() []string {
+	if strings.Contains(t.Expression, "=") {
+		return nil
+	}
+
+	paths    := []string{}
+	segments := []string{}
+	for _, segment := range strings.Split(t.Expression, TAG_PATH_SEPARATOR) {
+		if segment != "" {
+			segments = append(segments, segment)
+			paths    = append(paths, strings.Join(segments, TAG_PATH_SEPARATOR))
+		}
+	}
+
+	if len(paths) < 1 {
+		return nil
+	}
+	return paths
+}
+
+
+/* Filter tags match equal tags and, for statements, all their descendants */
+func (t *Tag) Matches(filterTag Tag) bool {
+	if t.Expression == filterTag.Expression {
+		return true
+	}
+
+	/* Statements are compared by their paths, so that empty segments do not matter, like in the tag tree */
+	filterPaths := filterTag.Paths()
+	if len(filterPaths) < 1 {
+		return false
+	}
+	for _, path := range t.Paths() {
+		if path == filterPaths[len(filterPaths) - 1] {
+			return true
+		}
+	}
+	return false
+}
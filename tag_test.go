@@ -0,0 +1,56 @@
+package main
+
+/* Tests of tag paths and filter matching */
+
+
+/* ================================================================================ Imports */
+import (
+	"reflect"
+	"testing"
+)
+
+
+/* ================================================================================ Tests */
+func TestTagPaths(t *testing.T) {
+	tests := []struct {
+		expression string
+		paths      []string
+	}{
+		{ "project",             []string{ "project" } },
+		{ "project/backend/api", []string{ "project", "project/backend", "project/backend/api" } },
+		{ "project//api",        []string{ "project", "project/api" } },
+		{ "/project/",           []string{ "project" } },
+		{ "/",                   nil },
+		{ "project/backend=api", nil },
+	}
+
+	for _, tt := range tests {
+		if paths := (&Tag{ tt.expression }).Paths(); !reflect.DeepEqual(paths, tt.paths) {
+			t.Errorf("Paths(%q) = %v, want %v", tt.expression, paths, tt.paths)
+		}
+	}
+}
+
+
+func TestTagMatches(t *testing.T) {
+	tests := []struct {
+		expression string
+		filter     string
+		matches    bool
+	}{
+		{ "project/api",  "project/api", true  },
+		{ "project/api",  "project",     true  },
+		{ "project",      "project/api", false },
+		{ "projects/api", "project",     false },
+		{ "project//api", "project/api", true  },
+		{ "project//api", "project",     true  },
+		{ "prio=high",    "prio=high",   true  },
+		{ "prio=high",    "prio",        false },
+	}
+
+	for _, tt := range tests {
+		if matches := (&Tag{ tt.expression }).Matches(Tag{ tt.filter }); matches != tt.matches {
+			t.Errorf("Matches(%q, %q) = %v, want %v", tt.expression, tt.filter, matches, tt.matches)
+		}
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+/* This file contains the tag completion entry, suggesting the keys and values of the tags used on the board (with their usage counts) while typing tag edit strings */
+
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	"fyne.io/fyne/v2/theme"
+)
+
+
+/* ================================================================================ Constants */
+const (
+	MAX_VISIBLE_TAG_SUGGESTIONS = 8
+)
+
+
+/* ================================================================================ Public types */
+type TagSuggestion struct {
+	Completion string
+	Count      int
+}
+
+
+/* Completes the last segment of the tag edit string, Down opens the suggestions, Up/Down/Enter pick one, Escape closes them */
+type TagCompletionEntry struct {
+	widget.Entry
+	TagCounts   func() map[string]int
+	suggestions []TagSuggestion
+	selected    int
+	list        *tagCompletionList
+	popUp       *widget.PopUp
+}
+
+
+/* ================================================================================ Private types */
+/* As pop-ups take the keyboard focus, the list passes typed keys on to its entry */
+type tagCompletionList struct {
+	widget.List
+	entry *TagCompletionEntry
+}
+
+
+/* ================================================================================ Public functions */
+func NewTagCompletionEntry(tagCounts func() map[string]int) *TagCompletionEntry {
+	entry := &TagCompletionEntry{ TagCounts: tagCounts }
+	entry.ExtendBaseWidget(entry)
+
+	return entry
+}
+
+
+/* Suggests keys (with "=" if used with values) or, once the segment contains "=", the values of its key, most used first */
+func TagSuggestions(counts map[string]int, segment string) []TagSuggestion {
+	segment = strings.TrimSpace(segment)
+	byCompletion := map[string]int{}
+
+	if segmentKey, valuePrefix, found := strings.Cut(segment, "="); found {
+		for expression, count := range counts {
+			tag                  := Tag{ expression }
+			key, value, hasValue := tag.KeyValue()
+			if hasValue && strings.EqualFold(key, strings.TrimSpace(segmentKey)) && hasPrefixFold(value, strings.TrimSpace(valuePrefix)) {
+				byCompletion[expression] += count
+			}
+		}
+	} else {
+		for expression, count := range counts {
+			tag              := Tag{ expression }
+			key, _, hasValue := tag.KeyValue()
+			if !hasPrefixFold(key, segment) {
+				continue
+			}
+			if hasValue {
+				key += "="
+			}
+			byCompletion[key] += count
+		}
+	}
+
+	suggestions := []TagSuggestion{}
+	for completion, count := range byCompletion {
+		suggestions = append(suggestions, TagSuggestion{ completion, count })
+	}
+	sort.Slice(suggestions,
+		func(i, j int) bool {
+			if suggestions[i].Count != suggestions[j].Count {
+				return suggestions[i].Count > suggestions[j].Count
+			}
+			return suggestions[i].Completion < suggestions[j].Completion
+		},
+	)
+
+	return suggestions
+}
+
+
+/* ================================================================================ Public methods */
+func (e *TagCompletionEntry) TypedRune(r rune) {
+	e.Entry.TypedRune(r)
+	e.updateSuggestions(false)
+}
+
+
+func (e *TagCompletionEntry) TypedKey(event *fyne.KeyEvent) {
+	if event.Name == fyne.KeyDown && !e.SuggestionsShown() {
+		e.updateSuggestions(true)
+		return
+	}
+
+	e.Entry.TypedKey(event)
+	if event.Name == fyne.KeyBackspace || event.Name == fyne.KeyDelete {
+		e.updateSuggestions(false)
+	}
+}
+
+
+func (e *TagCompletionEntry) FocusLost() {
+	e.HideSuggestions()
+	e.Entry.FocusLost()
+}
+
+
+func (e *TagCompletionEntry) SuggestionsShown() bool {
+	return e.popUp != nil && e.popUp.Visible()
+}
+
+
+func (e *TagCompletionEntry) HideSuggestions() {
+	if e.popUp != nil {
+		e.popUp.Hide()
+	}
+}
+
+
+/* Replaces the last segment by the suggestion, continuing with the values if a key was completed */
+func (e *TagCompletionEntry) ApplySuggestion(i int) {
+	if i < 0 || i >= len(e.suggestions) {
+		return
+	}
+
+	completion := e.suggestions[i].Completion
+	text       := e.Text
+	if separator := strings.LastIndex(text, ";"); separator >= 0 {
+		text = text[:separator + 1] + " " + completion
+	} else {
+		text = completion
+	}
+	if !strings.HasSuffix(completion, "=") {
+		text += "; "
+	}
+
+	e.HideSuggestions()
+	e.SetText(text)
+	e.CursorColumn = len([]rune(text))
+	e.Refresh()
+
+	if strings.HasSuffix(completion, "=") {
+		e.updateSuggestions(true)
+	}
+}
+
+
+/* ================================================================================ Private methods */
+func (e *TagCompletionEntry) updateSuggestions(show bool) {
+	if e.TagCounts == nil {
+		return
+	}
+
+	segment := e.Text[strings.LastIndex(e.Text, ";") + 1:]
+	if strings.TrimSpace(segment) == "" && !show {
+		e.HideSuggestions()
+		return
+	}
+
+	e.suggestions = TagSuggestions(e.TagCounts(), segment)
+	e.selected    = 0
+	if len(e.suggestions) < 1 {
+		e.HideSuggestions()
+		return
+	}
+
+	driver := fyne.CurrentApp().Driver()
+	canvas := driver.CanvasForObject(e)
+	if canvas == nil {
+		return
+	}
+
+	if e.popUp == nil {
+		e.list  = newTagCompletionList(e)
+		e.popUp = widget.NewPopUp(e.list, canvas)
+	}
+	e.list.Refresh()
+	e.list.ScrollToTop()
+
+	rowHeight := widget.NewLabel("").MinSize().Height + theme.SeparatorThicknessSize()
+	rowCount  := len(e.suggestions)
+	if rowCount > MAX_VISIBLE_TAG_SUGGESTIONS {
+		rowCount = MAX_VISIBLE_TAG_SUGGESTIONS
+	}
+
+	e.popUp.Resize(fyne.NewSize(e.Size().Width, rowHeight * float32(rowCount) + theme.Padding() * 2))
+	e.popUp.ShowAtPosition(driver.AbsolutePositionForObject(e).Add(fyne.NewPos(0, e.Size().Height)))
+	canvas.Focus(e.list)
+}
+
+
+func (e *TagCompletionEntry) highlightSuggestion(i int) {
+	if i < 0 || i >= len(e.suggestions) {
+		return
+	}
+
+	e.selected = i
+	e.list.Refresh()
+	e.list.ScrollTo(i)
+}
+
+
+func (e *TagCompletionEntry) completionKeyTyped(event *fyne.KeyEvent) {
+	switch event.Name {
+		case fyne.KeyDown:
+			e.highlightSuggestion(e.selected + 1)
+		case fyne.KeyUp:
+			e.highlightSuggestion(e.selected - 1)
+		case fyne.KeyReturn, fyne.KeyEnter:
+			e.ApplySuggestion(e.selected)
+		case fyne.KeyEscape:
+			e.HideSuggestions()
+		default:
+			e.TypedKey(event)
+	}
+}
+
+
+func (l *tagCompletionList) FocusGained() {
+}
+
+
+func (l *tagCompletionList) FocusLost() {
+}
+
+
+func (l *tagCompletionList) TypedRune(r rune) {
+	l.entry.TypedRune(r)
+}
+
+
+func (l *tagCompletionList) TypedKey(event *fyne.KeyEvent) {
+	l.entry.completionKeyTyped(event)
+}
+
+
+func (l *tagCompletionList) TypedShortcut(shortcut fyne.Shortcut) {
+	l.entry.TypedShortcut(shortcut)
+}
+
+
+/* ================================================================================ Private functions */
+func newTagCompletionList(entry *TagCompletionEntry) *tagCompletionList {
+	list := &tagCompletionList{ entry: entry }
+	list.Length = func() int {
+		return len(entry.suggestions)
+	}
+	list.CreateItem = func() fyne.CanvasObject {
+		return widget.NewLabel("")
+	}
+	/* The highlighted suggestion is bold, as selecting it in the list would keep it from being tapped */
+	list.UpdateItem = func(id widget.ListItemID, object fyne.CanvasObject) {
+		label          := object.(*widget.Label)
+		label.TextStyle = fyne.TextStyle{ Bold: id == entry.selected }
+		label.SetText(fmt.Sprintf("%s  (%d)", entry.suggestions[id].Completion, entry.suggestions[id].Count))
+	}
+	list.OnSelected = func(id widget.ListItemID) {
+		list.UnselectAll()
+		entry.ApplySuggestion(id)
+	}
+	list.ExtendBaseWidget(list)
+
+	return list
+}
+
+
+func hasPrefixFold(text, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(text), strings.ToLower(prefix))
+}
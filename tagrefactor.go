@@ -0,0 +1,159 @@
+package main
+
+/* This file contains the board-wide renaming and merging of tags, with a preview of the affected items */
+
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"strings"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+/* ================================================================================ Public functions */
+/* Returns the tag renamed from one of the source tags to the target tag, keys without value (e.g. "prio" to "priority") rename the key of all values */
+func RefactoredTag(tag Tag, sources []Tag, target Tag) (Tag, bool) {
+	key, value, hasValue         := tag.KeyValue()
+	targetKey, _, targetHasValue := target.KeyValue()
+
+	for _, source := range sources {
+		sourceKey, sourceValue, sourceHasValue := source.KeyValue()
+		if key != sourceKey {
+			continue
+		}
+
+		if !sourceHasValue && !targetHasValue {
+			if hasValue {
+				return Tag{ targetKey + "=" + value }, true
+			}
+			return Tag{ targetKey }, true
+		}
+
+		if hasValue == sourceHasValue && value == sourceValue {
+			return Tag{ strings.TrimSpace(target.Expression) }, true
+		}
+	}
+	return tag, false
+}
+
+
+/* Returns the refactored tags without duplicates (merged tags) and whether any tag changed */
+func RefactoredTags(tags []Tag, sources []Tag, target Tag) ([]Tag, bool) {
+	refactored := []Tag{}
+	changed    := false
+	for _, tag := range tags {
+		newTag, renamed := RefactoredTag(tag, sources, target)
+		changed = changed || renamed
+
+		duplicate := false
+		for _, existing := range refactored {
+			duplicate = duplicate || existing == newTag
+		}
+		if !duplicate {
+			refactored = append(refactored, newTag)
+		}
+	}
+	return refactored, changed
+}
+
+
+/* ================================================================================ Public methods */
+/* Lists the items (including archived ones) whose tags would change, with their tags before and after */
+func (w *Board) TagRefactoringPreview(sources []Tag, target Tag) []string {
+	lines := []string{}
+	for _, item := range w.tagRefactoringItems() {
+		if tags, changed := RefactoredTags(item.Tags, sources, target); changed {
+			lines = append(lines, fmt.Sprintf("%s:  %s  ->  %s", item.Title, ComposeTagEditString(item.Tags), ComposeTagEditString(tags)))
+		}
+	}
+	return lines
+}
+
+
+/* Renames (or merges) the source tags into the target tag on all items, in the vocabulary, the tag definitions and the filter, returning the number of items changed */
+func (w *Board) RefactorTags(sources []Tag, target Tag) int {
+	w.RecordUndoStep("Rename Tags to " + target.Expression)
+
+	count := 0
+	for _, item := range w.tagRefactoringItems() {
+		if tags, changed := RefactoredTags(item.Tags, sources, target); changed {
+			item.Tags = tags
+			item.Refresh()
+			count++
+		}
+	}
+
+	w.TagVocabulary, _ = RefactoredTags(w.TagVocabulary, sources, target)
+	w.FilterTags,    _ = RefactoredTags(w.FilterTags,    sources, target)
+
+	definitions := []TagDefinition{}
+	for _, definition := range w.TagDefinitions {
+		tag, _                := RefactoredTag(Tag{ definition.Expression }, sources, target)
+		definition.Expression = tag.Expression
+		if tagDefinitionIndex(definitions, definition.Expression) < 0 {
+			definitions = append(definitions, definition)
+		}
+	}
+	w.TagDefinitions = definitions
+
+	w.ApplyTagFilter()
+	if w.OnFilterChanged != nil {
+		w.OnFilterChanged(ComposeTagEditString(w.FilterTags))
+	}
+	w.RefreshItems()
+
+	return count
+}
+
+
+func (w *Board) ShowRefactorTagsDialog() {
+	sourcesEntry := NewTagCompletionEntry(w.TagCounts)
+	sourcesEntry.SetPlaceHolder("Tag1; Tag2=Value; ...")
+	targetEntry := NewTagCompletionEntry(w.TagCounts)
+	targetEntry.SetPlaceHolder("NewTag ...")
+
+	form := widget.NewForm(
+		widget.NewFormItem("Rename / Merge", sourcesEntry),
+		widget.NewFormItem("Into",           targetEntry),
+	)
+	form.Items[0].HintText = "Keys without value rename all their values"
+
+	dialog.ShowCustomConfirm("Rename / Merge Tags", "Preview", "Cancel", form,
+		func(confirmed bool) {
+			sources := ParseTagEditString(sourcesEntry.Text)
+			targets := ParseTagEditString(targetEntry.Text)
+			if !confirmed || len(sources) < 1 || len(targets) != 1 {
+				return
+			}
+
+			lines := w.TagRefactoringPreview(sources, targets[0])
+			if len(lines) < 1 {
+				ShowReportDialog("Rename / Merge Tags", "No items are tagged with " + ComposeTagEditString(sources), nil)
+				return
+			}
+
+			ShowReportConfirmDialog("Rename / Merge Tags", fmt.Sprintf("The tags of %d items will change:", len(lines)), lines, "Apply", "Cancel",
+				func(confirmed bool) {
+					if confirmed {
+						w.RefactorTags(sources, targets[0])
+					}
+				},
+			)
+		}, window,
+	)
+}
+
+
+/* ================================================================================ Private methods */
+func (w *Board) tagRefactoringItems() []*Item {
+	items := []*Item{}
+	for _, stage := range w.Stages {
+		items = append(items, stage.Items...)
+	}
+	for _, archivedItem := range w.Archive {
+		items = append(items, archivedItem.Item)
+	}
+	return items
+}
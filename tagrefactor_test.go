@@ -0,0 +1,68 @@
+package main
+
+/* Tests of renaming and merging tags */
+
+
+/* ================================================================================ Imports */
+import (
+	"reflect"
+	"testing"
+)
+
+
+/* ================================================================================ Tests */
+func TestRefactoredTag(t *testing.T) {
+	tests := []struct {
+		tag     string
+		sources []Tag
+		target  string
+		result  string
+		renamed bool
+	}{
+		{ "prio",      []Tag{ { "prio" } },              "priority", "priority",      true  },
+		{ "prio=high", []Tag{ { "prio" } },              "priority", "priority=high", true  },
+		{ "prio=low",  []Tag{ { "prio" } },              "priority", "priority=low",  true  },
+		{ "prio=high", []Tag{ { "prio=high" } },         "urgent",   "urgent",        true  },
+		{ "prio=high", []Tag{ { "prio=high" } },         "prio",     "prio",          true  },
+		{ "prio=low",  []Tag{ { "prio=high" } },         "urgent",   "prio=low",      false },
+		{ "prio",      []Tag{ { "prio=high" } },         "urgent",   "prio",          false },
+		{ "prio=high", []Tag{ { "prio" } },              "level=1",  "prio=high",     false },
+		{ "bug",       []Tag{ { "bug" }, { "defect" } }, "issue",    "issue",         true  },
+		{ "defect",    []Tag{ { "bug" }, { "defect" } }, "issue",    "issue",         true  },
+		{ "feature",   []Tag{ { "bug" }, { "defect" } }, "issue",    "feature",       false },
+		{ "bug",       []Tag{ { "bug" } },               "  issue ", "issue",         true  },
+	}
+
+	for _, tt := range tests {
+		result, renamed := RefactoredTag(Tag{ tt.tag }, tt.sources, Tag{ tt.target })
+		if result.Expression != tt.result || renamed != tt.renamed {
+			t.Errorf("RefactoredTag(%q, %v, %q) = %q, %v, want %q, %v", tt.tag, tt.sources, tt.target, result.Expression, renamed, tt.result, tt.renamed)
+		}
+	}
+}
+
+
+/* Merging into a tag the item already has keeps it once */
+func TestRefactoredTags(t *testing.T) {
+	tests := []struct {
+		tags    string
+		sources []Tag
+		target  string
+		result  string
+		changed bool
+	}{
+		{ "bug; urgent",              []Tag{ { "bug" } },               "issue",    "issue; urgent",               true  },
+		{ "bug; issue",               []Tag{ { "bug" } },               "issue",    "issue",                       true  },
+		{ "bug; defect; urgent",      []Tag{ { "bug" }, { "defect" } }, "issue",    "issue; urgent",               true  },
+		{ "prio=high; priority=high", []Tag{ { "prio" } },              "priority", "priority=high",               true  },
+		{ "prio=high; priority=low",  []Tag{ { "prio" } },              "priority", "priority=high; priority=low", true  },
+		{ "feature; urgent",          []Tag{ { "bug" } },               "issue",    "feature; urgent",             false },
+	}
+
+	for _, tt := range tests {
+		result, changed := RefactoredTags(ParseTagEditString(tt.tags), tt.sources, Tag{ tt.target })
+		if !reflect.DeepEqual(result, ParseTagEditString(tt.result)) || changed != tt.changed {
+			t.Errorf("RefactoredTags(%q, %v, %q) = %q, %v, want %q, %v", tt.tags, tt.sources, tt.target, ComposeTagEditString(result), changed, tt.result, tt.changed)
+		}
+	}
+}
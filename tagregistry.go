@@ -0,0 +1,295 @@
+package main
+
+/* This file contains the tag registry of boards, defining colors and descriptions per tag key (or key=value), and the tag manager dialog */
+
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"strings"
+	"image/color"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"fyne.io/fyne/v2/theme"
+)
+
+
+/* ================================================================================ Public types */
+/* Definitions of a key (e.g. "prio") apply to all its values, unless the value (e.g. "prio=high") is defined itself */
+type TagDefinition struct {
+	Expression  string
+	Style       *ItemStyle `json:",omitempty"`
+	Description string     `json:",omitempty"`
+}
+
+
+/* ================================================================================ Public methods */
+/* Returns the definition of the exact expression, or of its key, or nil */
+func (w *Board) TagDefinition(tag Tag) *TagDefinition {
+	if i := w.tagDefinitionIndex(tag.Expression); i >= 0 {
+		return &w.TagDefinitions[i]
+	}
+
+	if key, _, found := tag.KeyValue(); found {
+		if i := w.tagDefinitionIndex(key); i >= 0 {
+			return &w.TagDefinitions[i]
+		}
+	}
+	return nil
+}
+
+
+/* Returns the style defined for the tag, falling back to the inverted item style */
+func (w *Board) TagStyle(tag Tag, itemStyle ItemStyle) ItemStyle {
+	if definition := w.TagDefinition(tag); definition != nil && definition.Style != nil {
+		return *definition.Style
+	}
+	return ItemStyle{ itemStyle.Background, itemStyle.Foreground }
+}
+
+
+/* Adds the definition or replaces the one of the (previous) expression */
+func (w *Board) SetTagDefinition(previousExpression string, definition TagDefinition) {
+	w.RecordUndoStep("Define Tag " + definition.Expression)
+
+	if i := w.tagDefinitionIndex(previousExpression); i >= 0 {
+		w.TagDefinitions = append(w.TagDefinitions[:i], w.TagDefinitions[i+1:]...)
+	}
+	if i := w.tagDefinitionIndex(definition.Expression); i >= 0 {
+		w.TagDefinitions[i] = definition
+	} else {
+		w.TagDefinitions = append(w.TagDefinitions, definition)
+	}
+
+	w.RefreshItems()
+}
+
+
+/* Removes the definition and, if requested, the tag from all items */
+func (w *Board) RemoveTagDefinition(expression string, fromItems bool) {
+	w.RecordUndoStep("Remove Tag " + expression)
+
+	if i := w.tagDefinitionIndex(expression); i >= 0 {
+		w.TagDefinitions = append(w.TagDefinitions[:i], w.TagDefinitions[i+1:]...)
+	}
+
+	if fromItems {
+		for _, stage := range w.Stages {
+			for _, item := range stage.Items {
+				for i := itemTagIndex(item, Tag{ expression }); i >= 0; i = itemTagIndex(item, Tag{ expression }) {
+					item.Tags = append(item.Tags[:i], item.Tags[i+1:]...)
+				}
+			}
+		}
+		w.ApplyTagFilter()
+	}
+
+	w.RefreshItems()
+}
+
+
+/* Returns the tag vocabulary extended by the defined tags, e.g. for the item dialog */
+func (w *Board) VocabularyTags() []Tag {
+	tags := append([]Tag{}, w.TagVocabulary...)
+	for _, definition := range w.TagDefinitions {
+		known := false
+		for _, tag := range tags {
+			known = known || tag.Expression == definition.Expression
+		}
+		if !known {
+			tags = append(tags, Tag{ definition.Expression })
+		}
+	}
+	return tags
+}
+
+
+func (w *Board) RefreshItems() {
+	for _, stage := range w.Stages {
+		for _, item := range stage.Items {
+			item.Refresh()
+		}
+	}
+}
+
+
+func (w *Board) ShowTagManagerDialog() {
+	selected := -1
+	counts   := w.TagCounts()
+
+	list := widget.NewList(
+		func() int {
+			return len(w.TagDefinitions)
+		},
+		func() fyne.CanvasObject {
+			sample := NewCustomLabel(fyne.TextAlignCenter, PaintStyle{}, false, "", theme.CaptionTextSize(), fyne.TextStyle{ Italic: true }, Paddings{ 0.0, 1.0, 1.0, 0.5 }, Paddings{ 0.0, 0.0, 2.0, 2.0 })
+			return container.NewBorder(nil, nil, sample, nil, widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, object fyne.CanvasObject) {
+			definition := w.TagDefinitions[id]
+			style      := w.TagStyle(Tag{ definition.Expression }, DefaultItemStyle)
+			row        := object.(*fyne.Container)
+
+			tag    := Tag{ definition.Expression }
+			sample := row.Objects[1].(*CustomLabel)
+			sample.Text  = tag.DisplayString()
+			sample.Style = PaintStyle{ style.Foreground, style.Background, color.RGBA{ 0, 0, 0, 0 }, 1 }
+			sample.Refresh()
+
+			details := fmt.Sprintf("used %dx", w.tagUsageCount(definition.Expression, counts))
+			if definition.Description != "" {
+				details = definition.Description + "    (" + details + ")"
+			}
+			row.Objects[0].(*widget.Label).SetText(details)
+		},
+	)
+	list.OnSelected   = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(id widget.ListItemID) { selected = -1 }
+
+	refresh := func() {
+		counts   = w.TagCounts()
+		selected = -1
+		list.UnselectAll()
+		list.Refresh()
+	}
+
+	addButton := widget.NewButtonWithIcon("Add", theme.ContentAddIcon(),
+		func() {
+			w.showEditTagDefinitionDialog(TagDefinition{}, refresh)
+		},
+	)
+	editButton := widget.NewButtonWithIcon("Edit", theme.DocumentCreateIcon(),
+		func() {
+			if selected >= 0 && selected < len(w.TagDefinitions) {
+				w.showEditTagDefinitionDialog(w.TagDefinitions[selected], refresh)
+			}
+		},
+	)
+	removeButton := widget.NewButtonWithIcon("Remove", theme.DeleteIcon(),
+		func() {
+			if selected < 0 || selected >= len(w.TagDefinitions) {
+				return
+			}
+
+			expression := w.TagDefinitions[selected].Expression
+			ShowReportConfirmDialog("Remove Tag", fmt.Sprintf("Remove the definition of \"%s\" only, or the tag from all %d items using it too?", expression, w.tagUsageCount(expression, counts)), nil, "From Items Too", "Definition Only",
+				func(confirmed bool) {
+					w.RemoveTagDefinition(expression, confirmed)
+					refresh()
+				},
+			)
+		},
+	)
+
+	/* Tags used on the board can be taken over as definitions at once */
+	importButton := widget.NewButtonWithIcon("Add Used Tags", theme.ContentAddIcon(),
+		func() {
+			w.RecordUndoStep("Add Used Tags")
+			for _, expression := range w.SortedTagExpressions() {
+				if w.TagDefinition(Tag{ expression }) == nil {
+					w.TagDefinitions = append(w.TagDefinitions, TagDefinition{ Expression: expression })
+				}
+			}
+			refresh()
+		},
+	)
+
+	listSizer := canvas.NewRectangle(color.RGBA{ 0, 0, 0, 0 })
+	listSizer.SetMinSize(fyne.NewSize(500, 300))
+
+	dialogContainer := container.NewBorder(nil, container.NewGridWithColumns(4, addButton, editButton, removeButton, importButton), nil, nil, container.NewMax(listSizer, list))
+
+	dialog.ShowCustom("Tags", "Close", dialogContainer, window)
+}
+
+
+/* ================================================================================ Private methods */
+func (w *Board) tagDefinitionIndex(expression string) int {
+	return tagDefinitionIndex(w.TagDefinitions, expression)
+}
+
+
+/* Counts the items using the expression, or any value of it if it is a key */
+func (w *Board) tagUsageCount(expression string, counts map[string]int) int {
+	count := 0
+	for used, n := range counts {
+		if key, _, _ := (&Tag{ used }).KeyValue(); used == expression || key == expression {
+			count += n
+		}
+	}
+	return count
+}
+
+
+func (w *Board) showEditTagDefinitionDialog(definition TagDefinition, edited func()) {
+	expressionEntry := widget.NewSelectEntry(w.SortedTagExpressions())
+	expressionEntry.SetPlaceHolder("Key or Key=Value ...")
+	expressionEntry.SetText(definition.Expression)
+	descriptionEntry := widget.NewEntry()
+	descriptionEntry.SetPlaceHolder("Description ...")
+	descriptionEntry.SetText(definition.Description)
+
+	style := ItemStyle{ DefaultItemStyle.Background, DefaultItemStyle.Foreground }
+	if definition.Style != nil {
+		style = *definition.Style
+	}
+	styleCheck := widget.NewCheck("", nil)
+	styleCheck.SetChecked(definition.Style != nil)
+	foregroundColorButton := widget.NewButtonWithIcon("Foreground", theme.ColorPaletteIcon(),
+		func() {
+			ShowColorPickerDialog("Choose Foreground Color", "Please choose the color for the tag text.", style.Foreground,
+				func(selected color.RGBA) {
+					style.Foreground = selected
+					styleCheck.SetChecked(true)
+				},
+			)
+		},
+	)
+	backgroundColorButton := widget.NewButtonWithIcon("Background", theme.ColorPaletteIcon(),
+		func() {
+			ShowColorPickerDialog("Choose Background Color", "Please choose the color for the tag's background.", style.Background,
+				func(selected color.RGBA) {
+					style.Background = selected
+					styleCheck.SetChecked(true)
+				},
+			)
+		},
+	)
+
+	form := widget.NewForm(
+		widget.NewFormItem("Tag",         expressionEntry),
+		widget.NewFormItem("Description", descriptionEntry),
+		widget.NewFormItem("Colors",      container.NewBorder(nil, nil, styleCheck, nil, container.NewGridWithColumns(2, foregroundColorButton, backgroundColorButton))),
+	)
+
+	dialog.ShowCustomConfirm("Define Tag", "OK", "Cancel", form,
+		func(confirmed bool) {
+			expression := strings.TrimSpace(expressionEntry.Text)
+			if !confirmed || expression == "" {
+				return
+			}
+
+			newDefinition := TagDefinition{ Expression: expression, Description: strings.TrimSpace(descriptionEntry.Text) }
+			if styleCheck.Checked {
+				newDefinition.Style = &style
+			}
+
+			w.SetTagDefinition(definition.Expression, newDefinition)
+			edited()
+		}, window,
+	)
+}
+
+
+/* ================================================================================ Private functions */
+func tagDefinitionIndex(definitions []TagDefinition, expression string) int {
+	for i, definition := range definitions {
+		if definition.Expression == expression {
+			return i
+		}
+	}
+	return -1
+}
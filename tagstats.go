@@ -0,0 +1,270 @@
+package main
+
+/* This file contains the tag statistics of boards (item counts per tag and stage), their CSV export and the side panel showing them */
+
+
+/* ================================================================================ Imports */
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"sort"
+	"strconv"
+	"image/color"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"fyne.io/fyne/v2/theme"
+)
+
+
+/* ================================================================================ Public types */
+type TagStatistics struct {
+	Stages []string
+	Rows   []TagStatisticsRow
+}
+
+
+type TagStatisticsRow struct {
+	Tag         string
+	StageCounts []int
+	Total       int
+}
+
+
+/* The panel keeps its sort order while following the active board */
+type TagStatisticsPanel struct {
+	Container  *fyne.Container
+	statistics TagStatistics
+	signature  string
+	sortColumn int
+	descending bool
+	table      *widget.Table
+}
+
+
+/* ================================================================================ Public functions */
+func ExportBoardTagStatisticsCSV(board *Board) ([]byte, error) {
+	statistics := board.TagStatistics()
+	return statistics.CSV()
+}
+
+
+func NewTagStatisticsPanel() *TagStatisticsPanel {
+	panel := &TagStatisticsPanel{}
+
+	panel.table = widget.NewTable(
+		func() (int, int) {
+			return len(panel.statistics.Rows) + 1, len(panel.statistics.Stages) + 2
+		},
+		func() fyne.CanvasObject {
+			label         := widget.NewLabel("")
+			label.Wrapping = fyne.TextTruncate
+			return label
+		},
+		func(id widget.TableCellID, object fyne.CanvasObject) {
+			label          := object.(*widget.Label)
+			label.TextStyle = fyne.TextStyle{ Bold: id.Row == 0 || (id.Col == 0 && id.Row <= len(panel.statistics.Rows) && board != nil && board.FilterTagIndex(Tag{ panel.statistics.Rows[id.Row - 1].Tag }) >= 0) }
+			label.SetText(panel.cellText(id))
+		},
+	)
+	panel.table.SetColumnWidth(0, 160)
+	panel.table.OnSelected = func(id widget.TableCellID) {
+		panel.table.Unselect(id)
+		panel.cellTapped(id)
+	}
+
+	exportButton := widget.NewButtonWithIcon("", theme.DocumentSaveIcon(), showExportTagStatisticsDialog)
+	closeButton  := widget.NewButtonWithIcon("", theme.CancelIcon(), func() { panel.Container.Hide() })
+	titleLabel   := widget.NewLabelWithStyle("Tag Statistics", fyne.TextAlignLeading, fyne.TextStyle{ Italic: true })
+
+	tableSizer := canvas.NewRectangle(color.RGBA{ 0, 0, 0, 0 })
+	tableSizer.SetMinSize(fyne.NewSize(360, 0))
+
+	header         := container.NewHBox(titleLabel, layout.NewSpacer(), exportButton, closeButton)
+	panel.Container = container.NewBorder(header, nil, widget.NewSeparator(), nil, container.NewMax(tableSizer, panel.table))
+	panel.Container.Hide()
+
+	return panel
+}
+
+
+/* ================================================================================ Public methods */
+/* Counts the items per tag and stage, statements also count for their ancestors (e.g. "project/backend/api" for "project") */
+func (w *Board) TagStatistics() TagStatistics {
+	statistics := TagStatistics{}
+	rows       := map[string]*TagStatisticsRow{}
+
+	for _, stage := range w.Stages {
+		statistics.Stages = append(statistics.Stages, stage.Title)
+	}
+
+	for i, stage := range w.Stages {
+		for _, item := range stage.Items {
+			nodes := map[string]bool{}
+			for _, tag := range item.Tags {
+				paths := tag.Paths()
+				if paths == nil {
+					paths = []string{ tag.Expression }
+				}
+				for _, path := range paths {
+					nodes[path] = true
+				}
+			}
+
+			for node := range nodes {
+				row, found := rows[node]
+				if !found {
+					row        = &TagStatisticsRow{ Tag: node, StageCounts: make([]int, len(w.Stages)) }
+					rows[node] = row
+				}
+				row.StageCounts[i]++
+				row.Total++
+			}
+		}
+	}
+
+	for _, row := range rows {
+		statistics.Rows = append(statistics.Rows, *row)
+	}
+	statistics.Sort(0, false)
+
+	return statistics
+}
+
+
+/* Sorts by the tag (column 0), the count in a stage (columns 1 to n) or the total (last column), ties by tag */
+func (s *TagStatistics) Sort(column int, descending bool) {
+	value := func(row TagStatisticsRow) int {
+		if column > 0 && column <= len(row.StageCounts) {
+			return row.StageCounts[column - 1]
+		}
+		return row.Total
+	}
+
+	sort.SliceStable(s.Rows,
+		func(i, j int) bool {
+			a, b := s.Rows[i], s.Rows[j]
+			if column != 0 && value(a) != value(b) {
+				return (value(a) < value(b)) != descending
+			}
+			if column == 0 && descending {
+				return a.Tag > b.Tag
+			}
+			return a.Tag < b.Tag
+		},
+	)
+}
+
+
+func (s *TagStatistics) CSV() ([]byte, error) {
+	buffer := &bytes.Buffer{}
+	writer := csv.NewWriter(buffer)
+
+	if err := writer.Write(append(append([]string{ "Tag" }, s.Stages...), "Total")); err != nil {
+		return nil, err
+	}
+	for _, row := range s.Rows {
+		record := []string{ row.Tag }
+		for _, count := range row.StageCounts {
+			record = append(record, strconv.Itoa(count))
+		}
+		if err := writer.Write(append(record, strconv.Itoa(row.Total))); err != nil {
+			return nil, err
+		}
+	}
+
+	writer.Flush()
+	return buffer.Bytes(), writer.Error()
+}
+
+
+func (p *TagStatisticsPanel) Toggle() {
+	if p.Container.Visible() {
+		p.Container.Hide()
+	} else {
+		p.Container.Show()
+		p.Update(board)
+	}
+}
+
+
+/* Recomputes the statistics of the board, refreshing the table only if anything changed (as it is called on every change of the board or filter) */
+func (p *TagStatisticsPanel) Update(board *Board) {
+	if !p.Container.Visible() || board == nil {
+		return
+	}
+
+	statistics := board.TagStatistics()
+	statistics.Sort(p.sortColumn, p.descending)
+
+	data, _   := statistics.CSV()
+	signature := string(data) + ComposeTagEditString(board.FilterTags)
+	if signature == p.signature {
+		return
+	}
+
+	p.statistics = statistics
+	p.signature  = signature
+	p.table.Refresh()
+}
+
+
+/* ================================================================================ Private methods */
+func (p *TagStatisticsPanel) cellText(id widget.TableCellID) string {
+	lastColumn := len(p.statistics.Stages) + 1
+
+	if id.Row == 0 {
+		title := "Tag"
+		if id.Col == lastColumn {
+			title = "Total"
+		} else if id.Col > 0 {
+			title = p.statistics.Stages[id.Col - 1]
+		}
+
+		if id.Col == p.sortColumn || (id.Col == lastColumn && p.sortColumn >= lastColumn) {
+			if p.descending {
+				return title + " ▼"
+			}
+			return title + " ▲"
+		}
+		return title
+	}
+
+	if id.Row > len(p.statistics.Rows) {
+		return ""
+	}
+
+	row := p.statistics.Rows[id.Row - 1]
+	switch {
+		case id.Col == 0:
+			return row.Tag
+		case id.Col == lastColumn:
+			return fmt.Sprint(row.Total)
+		default:
+			return fmt.Sprint(row.StageCounts[id.Col - 1])
+	}
+}
+
+
+/* Tapping a column header sorts by it (again to reverse), tapping a row toggles the filter on its tag */
+func (p *TagStatisticsPanel) cellTapped(id widget.TableCellID) {
+	if id.Row == 0 {
+		if id.Col == p.sortColumn {
+			p.descending = !p.descending
+		} else {
+			p.sortColumn = id.Col
+			p.descending = id.Col != 0
+		}
+		p.signature = ""
+		p.Update(board)
+		return
+	}
+
+	if id.Row - 1 < len(p.statistics.Rows) {
+		board.ToggleFilterTag(Tag{ p.statistics.Rows[id.Row - 1].Tag })
+		p.Update(board)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+/* This file contains the tree view of the tag hierarchy (e.g. "project/backend/api"), with item counts, to toggle filters on tags and their descendants */
+
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"sort"
+	"image/color"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+/* ================================================================================ Public methods */
+/* Returns the sorted child nodes per node of the tag hierarchy, "" being the root and key=value expressions being leaves below it */
+func (w *Board) TagTree() map[string][]string {
+	known := map[string]bool{}
+	tree  := map[string][]string{}
+	add   := func(parent, node string) {
+		if !known[node] {
+			known[node]  = true
+			tree[parent] = append(tree[parent], node)
+		}
+	}
+
+	for expression := range w.TagCounts() {
+		tag   := Tag{ expression }
+		paths := tag.Paths()
+		if paths == nil {
+			add("", expression)
+			continue
+		}
+
+		parent := ""
+		for _, path := range paths {
+			add(parent, path)
+			parent = path
+		}
+	}
+
+	for _, children := range tree {
+		sort.Strings(children)
+	}
+	return tree
+}
+
+
+/* Returns the number of items per node of the tag hierarchy, counting items tagged with descendants too */
+func (w *Board) TagTreeCounts() map[string]int {
+	counts := map[string]int{}
+	for _, stage := range w.Stages {
+		for _, item := range stage.Items {
+			nodes := map[string]bool{}
+			for _, tag := range item.Tags {
+				paths := tag.Paths()
+				if paths == nil {
+					paths = []string{ tag.Expression }
+				}
+				for _, path := range paths {
+					nodes[path] = true
+				}
+			}
+
+			for node := range nodes {
+				counts[node]++
+			}
+		}
+	}
+	return counts
+}
+
+
+func (w *Board) ShowTagTreeDialog() {
+	tagTree := w.TagTree()
+	counts  := w.TagTreeCounts()
+
+	var tree *widget.Tree
+	tree = widget.NewTree(
+		func(node widget.TreeNodeID) []widget.TreeNodeID {
+			return tagTree[node]
+		},
+		func(node widget.TreeNodeID) bool {
+			return len(tagTree[node]) > 0
+		},
+		func(branch bool) fyne.CanvasObject {
+			return widget.NewLabel("")
+		},
+		/* Nodes show their last path segment, filtered ones in bold */
+		func(node widget.TreeNodeID, branch bool, object fyne.CanvasObject) {
+			segment := node
+			if paths := (&Tag{ node }).Paths(); len(paths) > 1 {
+				segment = node[len(paths[len(paths) - 2]) + len(TAG_PATH_SEPARATOR):]
+			}
+
+			label          := object.(*widget.Label)
+			label.TextStyle = fyne.TextStyle{ Bold: w.FilterTagIndex(Tag{ node }) >= 0 }
+			label.SetText(fmt.Sprintf("%s  (%d)", segment, counts[node]))
+		},
+	)
+	tree.OnSelected = func(node widget.TreeNodeID) {
+		w.ToggleFilterTag(Tag{ node })
+		tree.UnselectAll()
+		tree.Refresh()
+	}
+	tree.OpenAllBranches()
+
+	treeSizer := canvas.NewRectangle(color.RGBA{ 0, 0, 0, 0 })
+	treeSizer.SetMinSize(fyne.NewSize(400, 400))
+
+	dialog.ShowCustom("Tag Tree", "Close", container.NewMax(treeSizer, tree), window)
+}
@@ -6,6 +6,7 @@ package main
 /* ================================================================================ Imports */
 import (
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
 )
 
 
@@ -13,6 +14,7 @@ import (
 type TappableCustomLabel struct {
 	CustomLabel
 	OnTapped func()
+	Modifier desktop.Modifier
 }
 
 
@@ -21,7 +23,7 @@ func NewTappableCustomLabel(alignment fyne.TextAlign, style PaintStyle, lineWrap
 	backgroundPaddings, textPaddings := CalculatePaddings(paddingMultipliers, textPaddingOffsets)
 
 	customLabel         := CustomLabel{ Alignment: alignment, Style: style, LineWrapping: lineWrapping, Text: text, TextSize: textSize, TextStyle: textStyle, BackgroundPaddings: backgroundPaddings, TextPaddings: textPaddings }
-	tappableCustomLabel := &TappableCustomLabel{ customLabel, tapped }
+	tappableCustomLabel := &TappableCustomLabel{ customLabel, tapped, 0 }
 	tappableCustomLabel.ExtendBaseWidget(tappableCustomLabel)
 
 	return tappableCustomLabel
@@ -33,4 +35,14 @@ func (w *TappableCustomLabel) Tapped(event *fyne.PointEvent) {
 	if w.OnTapped != nil {
 		w.OnTapped()
 	}
+}
+
+
+/* Remembers the modifier keys held on the last mouse down, so tap handlers can distinguish e.g. Ctrl+click */
+func (w *TappableCustomLabel) MouseDown(event *desktop.MouseEvent) {
+	w.Modifier = event.Modifier
+}
+
+
+func (w *TappableCustomLabel) MouseUp(event *desktop.MouseEvent) {
 }
\ No newline at end of file
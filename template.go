@@ -0,0 +1,125 @@
+package main
+
+/* This file contains board templates (predefined stages, WIP limits, tag vocabulary and item templates) and item templates to prefill new items */
+
+
+/* ================================================================================ Imports */
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"fyne.io/fyne/v2"
+)
+
+
+/* ================================================================================ Public types */
+type StageTemplate struct {
+	Title    string
+	WipLimit int `json:",omitempty"`
+}
+
+
+type ItemTemplate struct {
+	Name        string
+	TitlePrefix string
+	Tags        []Tag
+	Description string
+	Style       ItemStyle
+}
+
+
+type BoardTemplate struct {
+	Name           string
+	Stages         []StageTemplate
+	TagVocabulary  []Tag           `json:",omitempty"`
+	TagDefinitions []TagDefinition `json:",omitempty"`
+	ItemTemplates  []ItemTemplate  `json:",omitempty"`
+}
+
+
+/* ================================================================================ Public variables */
+var BuiltinBoardTemplates = []BoardTemplate{
+	{ Name: "Empty Board" },
+	{
+		Name:   "Kanban",
+		Stages: []StageTemplate{ { "To Do", 0 }, { "In Progress", 3 }, { "Done", 0 } },
+	},
+	{
+		Name:          "Scrum",
+		Stages:        []StageTemplate{ { "Backlog", 0 }, { "Sprint Backlog", 0 }, { "In Progress", 3 }, { "Review", 2 }, { "Done", 0 } },
+		TagVocabulary: []Tag{ { "story" }, { "bug" }, { "points=1" }, { "points=2" }, { "points=3" }, { "points=5" }, { "points=8" } },
+		ItemTemplates: []ItemTemplate{
+			{ "User Story", "As a ", []Tag{ { "story" } }, "As a ... I want ... so that ...\n\nAcceptance criteria:\n- ", DefaultItemStyle },
+			{ "Bug", "Bug: ", []Tag{ { "bug" } }, "Steps to reproduce:\n1. \n\nExpected:\n\nActual:\n", ItemStyle{ color.RGBA{ 0, 0, 0, 255 }, color.RGBA{ 255, 153, 153, 255 } } },
+		},
+	},
+	{
+		Name:          "Bug Tracking",
+		Stages:        []StageTemplate{ { "New", 0 }, { "Confirmed", 0 }, { "In Progress", 5 }, { "Resolved", 0 }, { "Closed", 0 } },
+		TagVocabulary: []Tag{ { "severity=low" }, { "severity=medium" }, { "severity=high" }, { "severity=critical" }, { "regression" } },
+		ItemTemplates: []ItemTemplate{
+			{ "Bug", "", []Tag{ { "severity=medium" } }, "Version:\n\nSteps to reproduce:\n1. \n\nExpected:\n\nActual:\n", ItemStyle{ color.RGBA{ 0, 0, 0, 255 }, color.RGBA{ 255, 153, 153, 255 } } },
+			{ "Feature Request", "", nil, "Motivation:\n\nProposal:\n", DefaultItemStyle },
+		},
+	},
+}
+
+
+/* ================================================================================ Public functions */
+/* Creates a template from the structure of the board, without its items */
+func NewBoardTemplate(name string, board *Board) BoardTemplate {
+	template := BoardTemplate{ Name: name, TagVocabulary: board.TagVocabulary, TagDefinitions: board.TagDefinitions, ItemTemplates: board.ItemTemplates }
+
+	for _, stage := range board.Stages {
+		template.Stages = append(template.Stages, StageTemplate{ stage.Title, stage.WipLimit })
+	}
+
+	return template
+}
+
+
+func NewItemTemplate(name string, item *Item) ItemTemplate {
+	return ItemTemplate{ name, item.Title, append([]Tag{}, item.Tags...), item.Description, item.Style }
+}
+
+
+/* Templates saved by the user are stored in the preferences, as they are not bound to a board file */
+func UserBoardTemplates() []BoardTemplate {
+	templates := []BoardTemplate{}
+
+	if data := fyne.CurrentApp().Preferences().String("boardTemplates"); data != "" {
+		if err := json.Unmarshal([]byte(data), &templates); err != nil {
+			fmt.Println(err)
+		}
+	}
+	return templates
+}
+
+
+func SetUserBoardTemplates(templates []BoardTemplate) {
+	data, err := json.Marshal(templates)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fyne.CurrentApp().Preferences().SetString("boardTemplates", string(data))
+}
+
+
+func BoardTemplates() []BoardTemplate {
+	return append(append([]BoardTemplate{}, BuiltinBoardTemplates...), UserBoardTemplates()...)
+}
+
+
+/* ================================================================================ Public methods */
+func (t *BoardTemplate) Apply(board *Board) {
+	for _, stageTemplate := range t.Stages {
+		board.AppendStage(stageTemplate.Title)
+		board.Stages[len(board.Stages) - 1].WipLimit = stageTemplate.WipLimit
+	}
+
+	board.TagVocabulary  = append([]Tag{}, t.TagVocabulary...)
+	board.TagDefinitions = append([]TagDefinition{}, t.TagDefinitions...)
+	board.ItemTemplates  = append([]ItemTemplate{}, t.ItemTemplates...)
+}
@@ -0,0 +1,358 @@
+package main
+
+/* This file contains the two-way synchronization between a board and a todo.txt file, using a configurable mapping of todo.txt elements to tags and stages */
+
+
+/* ================================================================================ Imports */
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+/* ================================================================================ Public types */
+type TodoTxtMapping struct {
+	File         string
+	ProjectKey   string
+	ContextKey   string
+	PriorityKey  string
+	DoneStage    string
+	DefaultStage string
+	SyncedIds    []string `json:",omitempty"`
+}
+
+
+/* ================================================================================ Private types */
+type todoTxtTask struct {
+	Done  bool
+	Id    string
+	Title string
+	Tags  []Tag
+}
+
+
+/* ================================================================================ Private variables */
+var todoTxtDatePattern     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+var todoTxtPriorityPattern = regexp.MustCompile(`^\(([A-Z])\)$`)
+var todoTxtNumberPattern   = regexp.MustCompile(`^[\d.,]+$`)
+
+
+/* ================================================================================ Public functions */
+func NewTodoTxtMapping() *TodoTxtMapping {
+	return &TodoTxtMapping{ ProjectKey: "project", ContextKey: "context", PriorityKey: "priority", DoneStage: "Done", DefaultStage: "Todo" }
+}
+
+
+/* Updates items matched by ID (or by title for tasks without ID), creates items for all other tasks and removes the items synchronized before whose tasks were deleted */
+func ImportTodoTxt(board *Board, data []byte, mapping *TodoTxtMapping) (created, updated, removed int) {
+	scanner     := bufio.NewScanner(bytes.NewReader(data))
+	taskIds     := map[string]bool{}
+	targetStage := func(task todoTxtTask) *Stage {
+		if task.Done {
+			return ensureTodoTxtStage(board, mapping.DoneStage)
+		}
+		return ensureTodoTxtStage(board, mapping.DefaultStage)
+	}
+
+	for scanner.Scan() {
+		task, valid := parseTodoTxtLine(scanner.Text(), mapping)
+		if !valid {
+			continue
+		}
+		taskIds[task.Id] = true
+
+		/* Archived items stay archived, their tasks are dropped from the file on export */
+		if task.Id != "" && board.archivedItemById(task.Id) != nil {
+			continue
+		}
+
+		item := board.ItemById(task.Id)
+		if item == nil && task.Id == "" {
+			item = board.itemByTitle(task.Title)
+		}
+
+		if item == nil {
+			stage := targetStage(task)
+			item   = stage.AppendItem(task.Title, task.Tags, "", DefaultItemStyle)
+			if task.Id != "" {
+				item.Id = task.Id
+			}
+			stage.Rules.ApplyEnter(item)
+			created++
+			continue
+		}
+
+		item.Title = task.Title
+		item.Tags  = task.Tags
+		item.Refresh()
+
+		/* Only move items if their completion changed, so the stage of open items is kept */
+		isDone := board.ItemStage(item).Title == mapping.DoneStage
+		if isDone != task.Done {
+			if err := board.MoveItem(item, targetStage(task), nil, true); err != nil {
+				fmt.Println(err)
+			}
+		}
+		updated++
+	}
+
+	for _, id := range mapping.SyncedIds {
+		if item := board.ItemById(id); item != nil && !taskIds[id] {
+			board.RemoveItem(item)
+			removed++
+		}
+	}
+
+	board.ApplyTagFilter()
+
+	return created, updated, removed
+}
+
+
+/* Remembers the IDs of the exported items, to remove them on the next import if their tasks were deleted from the file */
+func ExportTodoTxt(board *Board, mapping *TodoTxtMapping) []byte {
+	mapping.SyncedIds = nil
+	buffer := &bytes.Buffer{}
+
+	for _, stage := range board.Stages {
+		for _, item := range stage.Items {
+			fmt.Fprintln(buffer, composeTodoTxtLine(item, stage.Title == mapping.DoneStage, mapping))
+			mapping.SyncedIds = append(mapping.SyncedIds, item.Id)
+		}
+	}
+
+	return buffer.Bytes()
+}
+
+
+/* ================================================================================ Public methods */
+func (w *Board) ShowTodoTxtSettingsDialog(confirmedCallback func()) {
+	mapping := w.TodoTxt
+	if mapping == nil {
+		mapping = NewTodoTxtMapping()
+	}
+
+	stageTitles := make([]string, len(w.Stages))
+	for i, stage := range w.Stages {
+		stageTitles[i] = stage.Title
+	}
+
+	fileEntry := widget.NewEntry()
+	fileEntry.SetPlaceHolder("Path to todo.txt ...")
+	if uri, err := storage.ParseURI(mapping.File); err == nil && mapping.File != "" {
+		fileEntry.SetText(uri.Path())
+	}
+
+	fileButton := widget.NewButton("Choose ...",
+		func() {
+			fileDialog := dialog.NewFileOpen(
+				func(reader fyne.URIReadCloser, err error) {
+					if reader != nil && err == nil {
+						fileEntry.SetText(reader.URI().Path())
+						reader.Close()
+					}
+				}, window,
+			)
+			fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{ ".txt" }))
+			fileDialog.Show()
+		},
+	)
+
+	projectKeyEntry := widget.NewEntry()
+	projectKeyEntry.SetText(mapping.ProjectKey)
+	contextKeyEntry := widget.NewEntry()
+	contextKeyEntry.SetText(mapping.ContextKey)
+	priorityKeyEntry := widget.NewEntry()
+	priorityKeyEntry.SetText(mapping.PriorityKey)
+	doneStageEntry := widget.NewSelectEntry(stageTitles)
+	doneStageEntry.SetText(mapping.DoneStage)
+	defaultStageEntry := widget.NewSelectEntry(stageTitles)
+	defaultStageEntry.SetText(mapping.DefaultStage)
+
+	form := widget.NewForm(
+		widget.NewFormItem("File",             container.NewBorder(nil, nil, nil, fileButton, fileEntry)),
+		widget.NewFormItem("Project Tag Key",  projectKeyEntry),
+		widget.NewFormItem("Context Tag Key",  contextKeyEntry),
+		widget.NewFormItem("Priority Tag Key", priorityKeyEntry),
+		widget.NewFormItem("Done Stage",       doneStageEntry),
+		widget.NewFormItem("New Items Stage",  defaultStageEntry),
+	)
+
+	dialog.ShowCustomConfirm("todo.txt Settings", "OK", "Cancel", form,
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+
+			file := ""
+			if path := strings.TrimSpace(fileEntry.Text); path != "" {
+				file = storage.NewFileURI(path).String()
+			}
+
+			/* Another file has not been synchronized yet, so none of its tasks can have been deleted */
+			syncedIds := mapping.SyncedIds
+			if file != mapping.File {
+				syncedIds = nil
+			}
+
+			w.RecordUndoStep("Edit todo.txt Settings")
+			w.TodoTxt = &TodoTxtMapping{ file, strings.TrimSpace(projectKeyEntry.Text), strings.TrimSpace(contextKeyEntry.Text), strings.TrimSpace(priorityKeyEntry.Text), doneStageEntry.Text, defaultStageEntry.Text, syncedIds }
+
+			if confirmedCallback != nil {
+				confirmedCallback()
+			}
+		}, window,
+	)
+}
+
+
+/* ================================================================================ Private methods */
+func (w *Board) itemByTitle(title string) *Item {
+	for _, stage := range w.Stages {
+		for _, item := range stage.Items {
+			if item.Title == title {
+				return item
+			}
+		}
+	}
+	return nil
+}
+
+
+/* ================================================================================ Private functions */
+func ensureTodoTxtStage(board *Board, title string) *Stage {
+	stage := board.StageByTitle(title)
+	if stage == nil {
+		board.AppendStage(title)
+		stage = board.Stages[len(board.Stages) - 1]
+	}
+	return stage
+}
+
+
+/* Empty keys map to tags without value (and back on export) */
+func todoTxtMappedTag(key, value string) Tag {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return Tag{ value }
+	}
+	return Tag{ key + "=" + value }
+}
+
+
+func parseTodoTxtLine(line string, mapping *TodoTxtMapping) (todoTxtTask, bool) {
+	task   := todoTxtTask{}
+	words  := strings.Fields(line)
+	titles := []string{}
+
+	if len(words) < 1 {
+		return task, false
+	}
+
+	if words[0] == "x" {
+		task.Done = true
+		words     = words[1:]
+
+		/* Skip the completion and creation dates */
+		for i := 0; i < 2 && len(words) > 0 && todoTxtDatePattern.MatchString(words[0]); i++ {
+			words = words[1:]
+		}
+	}
+
+	if len(words) > 0 && todoTxtPriorityPattern.MatchString(words[0]) {
+		task.Tags = append(task.Tags, todoTxtMappedTag(mapping.PriorityKey, todoTxtPriorityPattern.FindStringSubmatch(words[0])[1]))
+		words     = words[1:]
+	}
+
+	/* Skip the creation date */
+	if len(words) > 0 && todoTxtDatePattern.MatchString(words[0]) {
+		words = words[1:]
+	}
+
+	for _, word := range words {
+		key, value, found := strings.Cut(word, ":")
+
+		switch {
+			case len(word) > 1 && word[0] == '+':
+				task.Tags = append(task.Tags, todoTxtMappedTag(mapping.ProjectKey, word[1:]))
+
+			case len(word) > 1 && word[0] == '@':
+				task.Tags = append(task.Tags, todoTxtMappedTag(mapping.ContextKey, word[1:]))
+
+			/* Numeric keys are no extensions but e.g. times like "10:30" */
+			case found && key != "" && value != "" && !todoTxtNumberPattern.MatchString(key) && !strings.Contains(key, "/") && !strings.HasPrefix(value, "//"):
+				switch key {
+					case "id":
+						task.Id = value
+					case "tag":
+						task.Tags = append(task.Tags, Tag{ value })
+					case "pri":
+						task.Tags = append(task.Tags, todoTxtMappedTag(mapping.PriorityKey, value))
+					default:
+						task.Tags = append(task.Tags, Tag{ key + "=" + value })
+				}
+
+			default:
+				titles = append(titles, word)
+		}
+	}
+
+	task.Title = strings.Join(titles, " ")
+
+	return task, true
+}
+
+
+func composeTodoTxtLine(item *Item, done bool, mapping *TodoTxtMapping) string {
+	words    := []string{}
+	priority := ""
+
+	for _, tag := range item.Tags {
+		key, value, found := strings.Cut(tag.Expression, "=")
+		if !found {
+			/* Tags without value match an empty key, like on import */
+			key, value = "", key
+		}
+		key   = strings.TrimSpace(key)
+		value = strings.ReplaceAll(strings.TrimSpace(value), " ", "_")
+
+		switch {
+			case key == strings.TrimSpace(mapping.ProjectKey):
+				words = append(words, "+" + value)
+			case key == strings.TrimSpace(mapping.ContextKey):
+				words = append(words, "@" + value)
+			/* Tasks have one priority, further ones are kept as extensions */
+			case key == strings.TrimSpace(mapping.PriorityKey) && priority == "":
+				priority = value
+			case found:
+				words = append(words, strings.ReplaceAll(key, " ", "_") + ":" + value)
+			default:
+				words = append(words, "tag:" + value)
+		}
+	}
+
+	words = append(words, "id:" + item.Id)
+
+	/* Completed tasks can not have a priority in todo.txt, so it is kept as pri extension instead */
+	prefix := ""
+	if done {
+		prefix = "x "
+		if priority != "" {
+			words = append(words, "pri:" + priority)
+		}
+	} else if len(priority) == 1 && priority[0] >= 'A' && priority[0] <= 'Z' {
+		prefix = "(" + priority + ") "
+	} else if priority != "" {
+		words = append(words, "pri:" + priority)
+	}
+
+	return prefix + strings.TrimSpace(item.Title + " " + strings.Join(words, " "))
+}
\ No newline at end of file
@@ -0,0 +1,160 @@
+package main
+
+/* Tests of parsing and composing todo.txt lines and of the synchronization */
+
+
+/* ================================================================================ Imports */
+import (
+	"reflect"
+	"testing"
+	"fyne.io/fyne/v2/test"
+)
+
+
+/* ================================================================================ Tests */
+func TestParseTodoTxtLine(t *testing.T) {
+	mapping := NewTodoTxtMapping()
+
+	tests := []struct {
+		line  string
+		valid bool
+		task  todoTxtTask
+	}{
+		{ "",                                            false, todoTxtTask{} },
+		{ "Call mom",                                    true,  todoTxtTask{ Title: "Call mom" } },
+		{ "(A) 2022-01-01 Call mom +family @phone",      true,  todoTxtTask{ Title: "Call mom", Tags: []Tag{ { "priority=A" }, { "project=family" }, { "context=phone" } } } },
+		{ "x 2022-01-02 2022-01-01 Call mom id:abc",     true,  todoTxtTask{ Done: true, Id: "abc", Title: "Call mom" } },
+		{ "Meeting at 10:30 due:2022-01-03 tag:work",    true,  todoTxtTask{ Title: "Meeting at 10:30", Tags: []Tag{ { "due=2022-01-03" }, { "work" } } } },
+		{ "Read https://example.com pri:high",           true,  todoTxtTask{ Title: "Read https://example.com", Tags: []Tag{ { "priority=high" } } } },
+	}
+
+	for _, tt := range tests {
+		task, valid := parseTodoTxtLine(tt.line, mapping)
+		if valid != tt.valid || !reflect.DeepEqual(task, tt.task) {
+			t.Errorf("parseTodoTxtLine(%q) = %+v, %v, want %+v, %v", tt.line, task, valid, tt.task, tt.valid)
+		}
+	}
+}
+
+
+func TestComposeTodoTxtLine(t *testing.T) {
+	mapping := NewTodoTxtMapping()
+
+	tests := []struct {
+		tags []Tag
+		done bool
+		line string
+	}{
+		{ nil,                                                                  false, "Call mom id:1" },
+		{ []Tag{ { "priority=A" }, { "project=family" }, { "context=phone" } }, false, "(A) Call mom +family @phone id:1" },
+		{ []Tag{ { "priority=A" } },                                            true,  "x Call mom id:1 pri:A" },
+		{ []Tag{ { "priority=high" }, { "urgent" }, { "due=2022-01-03" } },     false, "Call mom tag:urgent due:2022-01-03 id:1 pri:high" },
+	}
+
+	for _, tt := range tests {
+		item := &Item{ Id: "1", Title: "Call mom", Tags: tt.tags }
+		if line := composeTodoTxtLine(item, tt.done, mapping); line != tt.line {
+			t.Errorf("composeTodoTxtLine(%v, %v) = %q, want %q", tt.tags, tt.done, line, tt.line)
+		}
+
+		/* Composed lines parse back to the same item */
+		task, _ := parseTodoTxtLine(tt.line, mapping)
+		if task.Id != item.Id || task.Title != item.Title || len(task.Tags) != len(tt.tags) {
+			t.Errorf("parseTodoTxtLine(%q) = %+v, does not round-trip", tt.line, task)
+		}
+	}
+}
+
+
+/* Tags survive export and import with any mapping, including empty keys mapping to tags without value */
+func TestTodoTxtRoundTrip(t *testing.T) {
+	tags := []Tag{ { "bug" }, { "urgent" }, { "project=web" }, { "context=phone" }, { "priority=A" }, { "priority=high" }, { "due=2022-01-03" } }
+
+	tests := []struct {
+		projectKey  string
+		contextKey  string
+		priorityKey string
+		line        string
+	}{
+		{ "project", "context", "priority", "(A) Call mom tag:bug tag:urgent +web @phone priority:high due:2022-01-03 id:1" },
+		{ "",        "context", "priority", "(A) Call mom +bug +urgent project:web @phone priority:high due:2022-01-03 id:1" },
+		{ " ",       "context", "priority", "(A) Call mom +bug +urgent project:web @phone priority:high due:2022-01-03 id:1" },
+		{ "project", "",        "priority", "(A) Call mom @bug @urgent +web context:phone priority:high due:2022-01-03 id:1" },
+		{ "project", "context", "",         "Call mom tag:urgent +web @phone priority:A priority:high due:2022-01-03 id:1 pri:bug" },
+	}
+
+	for _, tt := range tests {
+		mapping := &TodoTxtMapping{ ProjectKey: tt.projectKey, ContextKey: tt.contextKey, PriorityKey: tt.priorityKey }
+		item    := &Item{ Id: "1", Title: "Call mom", Tags: tags }
+
+		line := composeTodoTxtLine(item, false, mapping)
+		if line != tt.line {
+			t.Errorf("composeTodoTxtLine() with keys %q, %q, %q = %q, want %q", tt.projectKey, tt.contextKey, tt.priorityKey, line, tt.line)
+		}
+
+		task, _ := parseTodoTxtLine(line, mapping)
+		if !sameTodoTxtTags(task.Tags, tags) {
+			t.Errorf("parseTodoTxtLine(%q) with keys %q, %q, %q = %v, want %v", line, tt.projectKey, tt.contextKey, tt.priorityKey, task.Tags, tags)
+		}
+	}
+}
+
+
+func TestImportTodoTxt(t *testing.T) {
+	test.NewApp()
+
+	mapping := NewTodoTxtMapping()
+	board   := NewBoard("Test", nil)
+	board.AppendStage("Todo")
+	kept    := board.Stages[0].AppendItem("Kept", nil, "", DefaultItemStyle)
+	deleted := board.Stages[0].AppendItem("Deleted", nil, "", DefaultItemStyle)
+	ExportTodoTxt(board, mapping)
+
+	created, updated, removed := ImportTodoTxt(board, []byte("Kept id:" + kept.Id + "\nx New\n"), mapping)
+	if created != 1 || updated != 1 || removed != 1 {
+		t.Errorf("ImportTodoTxt() = %d, %d, %d, want 1, 1, 1", created, updated, removed)
+	}
+	if board.ItemById(deleted.Id) != nil {
+		t.Errorf("item of deleted task was not removed")
+	}
+	if done := board.StageByTitle(mapping.DoneStage); done == nil || len(done.Items) != 1 {
+		t.Errorf("completed task was not created in the done stage")
+	}
+
+	/* Created items enter their stage like moved ones */
+	board.StageByTitle(mapping.DoneStage).Rules = &StageRules{ EnterAddTags: []Tag{ { "done" } } }
+	ImportTodoTxt(board, []byte("Kept id:" + kept.Id + "\nx New\nx Newer\n"), mapping)
+	if items := board.StageByTitle(mapping.DoneStage).Items; len(items) != 2 || itemTagIndex(items[1], Tag{ "done" }) < 0 {
+		t.Errorf("enter rules of the stage were not applied to the created item")
+	}
+
+	/* Completed tasks only need the done stage */
+	board.RemoveStage(board.Stages[0])
+	ImportTodoTxt(board, []byte("x Another\n"), mapping)
+	if board.StageByTitle(mapping.DefaultStage) != nil {
+		t.Errorf("default stage was created for a completed task")
+	}
+}
+
+
+/* ================================================================================ Private functions */
+/* Compares tags regardless of their order, as the export orders them by kind */
+func sameTodoTxtTags(a, b []Tag) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	counts := map[string]int{}
+	for _, tag := range a {
+		counts[tag.Expression]++
+	}
+	for _, tag := range b {
+		counts[tag.Expression]--
+	}
+	for _, count := range counts {
+		if count != 0 {
+			return false
+		}
+	}
+	return true
+}
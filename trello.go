@@ -0,0 +1,236 @@
+package main
+
+/* This file contains the import of board exports from Trello, mapping lists to stages, cards to items and labels to tags */
+
+
+/* ================================================================================ Imports */
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"image/color"
+)
+
+
+/* ================================================================================ Private types */
+type trelloLabel struct {
+	Id    string `json:"id"`
+	Name  string `json:"name"`
+	Color string `json:"color"`
+}
+
+
+type trelloList struct {
+	Id     string  `json:"id"`
+	Name   string  `json:"name"`
+	Closed bool    `json:"closed"`
+	Pos    float64 `json:"pos"`
+}
+
+
+type trelloCard struct {
+	Id           string        `json:"id"`
+	Name         string        `json:"name"`
+	Desc         string        `json:"desc"`
+	Closed       bool          `json:"closed"`
+	IdList       string        `json:"idList"`
+	IdLabels     []string      `json:"idLabels"`
+	Labels       []trelloLabel `json:"labels"`
+	IdChecklists []string      `json:"idChecklists"`
+	IdMembers    []string      `json:"idMembers"`
+	Due          string        `json:"due"`
+	Pos          float64       `json:"pos"`
+	Badges       struct {
+		Attachments int `json:"attachments"`
+		Comments    int `json:"comments"`
+	} `json:"badges"`
+}
+
+
+type trelloCheckItem struct {
+	Name  string  `json:"name"`
+	State string  `json:"state"`
+	Pos   float64 `json:"pos"`
+}
+
+
+type trelloChecklist struct {
+	Id         string            `json:"id"`
+	IdCard     string            `json:"idCard"`
+	Name       string            `json:"name"`
+	Pos        float64           `json:"pos"`
+	CheckItems []trelloCheckItem `json:"checkItems"`
+}
+
+
+type trelloBoard struct {
+	Name       string            `json:"name"`
+	Lists      []trelloList      `json:"lists"`
+	Cards      []trelloCard      `json:"cards"`
+	Labels     []trelloLabel     `json:"labels"`
+	Checklists []trelloChecklist `json:"checklists"`
+}
+
+
+/* ================================================================================ Private variables */
+var trelloLabelColors = map[string]color.RGBA{
+	"green":  { 97, 189, 79, 255 },
+	"yellow": { 242, 214, 0, 255 },
+	"orange": { 255, 159, 26, 255 },
+	"red":    { 235, 90, 70, 255 },
+	"purple": { 195, 119, 224, 255 },
+	"blue":   { 0, 121, 191, 255 },
+	"sky":    { 0, 194, 224, 255 },
+	"lime":   { 81, 232, 152, 255 },
+	"pink":   { 255, 120, 203, 255 },
+	"black":  { 52, 69, 99, 255 },
+}
+
+
+/* ================================================================================ Public functions */
+func ImportTrelloBoard(board *Board, data []byte) ([]string, error) {
+	var export trelloBoard
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+
+	report := []string{}
+
+	labels := make(map[string]trelloLabel, len(export.Labels))
+	for _, label := range export.Labels {
+		labels[label.Id] = label
+	}
+
+	checklists := make(map[string][]trelloChecklist)
+	for _, checklist := range export.Checklists {
+		checklists[checklist.IdCard] = append(checklists[checklist.IdCard], checklist)
+	}
+
+	lists := append([]trelloList{}, export.Lists...)
+	sort.SliceStable(lists, func(i, j int) bool { return lists[i].Pos < lists[j].Pos })
+
+	cards := append([]trelloCard{}, export.Cards...)
+	sort.SliceStable(cards, func(i, j int) bool { return cards[i].Pos < cards[j].Pos })
+
+	board.Clear()
+	board.Name = export.Name
+
+	stages := make(map[string]*Stage, len(lists))
+	for _, list := range lists {
+		if list.Closed {
+			report = append(report, fmt.Sprintf("Skipped archived list \"%s\"", list.Name))
+			continue
+		}
+
+		board.AppendStage(list.Name)
+		stages[list.Id] = board.Stages[len(board.Stages) - 1]
+	}
+
+	for _, card := range cards {
+		if card.Closed {
+			report = append(report, fmt.Sprintf("Skipped archived card \"%s\"", card.Name))
+			continue
+		}
+
+		stage, found := stages[card.IdList]
+		if !found {
+			report = append(report, fmt.Sprintf("Skipped card \"%s\" of unknown or archived list", card.Name))
+			continue
+		}
+
+		tags, style, unmapped := mapTrelloCardLabels(card, labels)
+		for _, unmappedColor := range unmapped {
+			report = append(report, fmt.Sprintf("Could not map color \"%s\" of card \"%s\"", unmappedColor, card.Name))
+		}
+
+		if len(card.Due) >= 10 {
+			tags = append(tags, Tag{ "due=" + card.Due[:10] })
+		}
+
+		description := composeTrelloCardDescription(card, checklists[card.Id])
+
+		if len(card.IdMembers) > 0 {
+			report = append(report, fmt.Sprintf("Could not map %d member(s) of card \"%s\"", len(card.IdMembers), card.Name))
+		}
+		if card.Badges.Attachments > 0 {
+			report = append(report, fmt.Sprintf("Could not map %d attachment(s) of card \"%s\"", card.Badges.Attachments, card.Name))
+		}
+		if card.Badges.Comments > 0 {
+			report = append(report, fmt.Sprintf("Could not map %d comment(s) of card \"%s\"", card.Badges.Comments, card.Name))
+		}
+
+		stage.AppendItem(card.Name, tags, description, style)
+	}
+
+	board.Refresh()
+
+	return report, nil
+}
+
+
+/* ================================================================================ Private functions */
+func mapTrelloCardLabels(card trelloCard, labels map[string]trelloLabel) (tags []Tag, style ItemStyle, unmappedColors []string) {
+	cardLabels := card.Labels
+	if len(cardLabels) < 1 {
+		for _, id := range card.IdLabels {
+			if label, found := labels[id]; found {
+				cardLabels = append(cardLabels, label)
+			}
+		}
+	}
+
+	style        = DefaultItemStyle
+	styleMapped := false
+
+	for _, label := range cardLabels {
+		if label.Name != "" {
+			tags = append(tags, Tag{ strings.ReplaceAll(label.Name, ";", ",") })
+		} else if label.Color != "" {
+			tags = append(tags, Tag{ "label=" + label.Color })
+		}
+
+		if styleMapped || label.Color == "" {
+			continue
+		}
+
+		background, found := trelloLabelColors[strings.TrimSuffix(strings.TrimSuffix(label.Color, "_light"), "_dark")]
+		if !found {
+			unmappedColors = append(unmappedColors, label.Color)
+			continue
+		}
+
+		style       = ItemStyle{ ContrastColor(background), background }
+		styleMapped = true
+	}
+
+	return tags, style, unmappedColors
+}
+
+
+func composeTrelloCardDescription(card trelloCard, checklists []trelloChecklist) string {
+	buffer := &strings.Builder{}
+	buffer.WriteString(strings.TrimSpace(card.Desc))
+
+	sort.SliceStable(checklists, func(i, j int) bool { return checklists[i].Pos < checklists[j].Pos })
+
+	for _, checklist := range checklists {
+		if buffer.Len() > 0 {
+			buffer.WriteString("\n\n")
+		}
+		fmt.Fprintf(buffer, "%s:", checklist.Name)
+
+		checkItems := checklist.CheckItems
+		sort.SliceStable(checkItems, func(i, j int) bool { return checkItems[i].Pos < checkItems[j].Pos })
+
+		for _, checkItem := range checkItems {
+			mark := " "
+			if checkItem.State == "complete" {
+				mark = "x"
+			}
+			fmt.Fprintf(buffer, "\n[%s] %s", mark, checkItem.Name)
+		}
+	}
+
+	return buffer.String()
+}
\ No newline at end of file
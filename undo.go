@@ -0,0 +1,130 @@
+package main
+
+/* This file contains the undo history of boards, which records snapshots of the board data (and the filter, as e.g. renaming tags changes it) before every change and thereby tracks their modified state */
+
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+)
+
+
+/* ================================================================================ Constants */
+const (
+	MAX_UNDO_STEPS = 50
+)
+
+
+/* ================================================================================ Private types */
+type undoStep struct {
+	description string
+	data        []byte
+	filterTags  []Tag
+}
+
+
+/* ================================================================================ Public methods */
+/* Remembers the current state of the board, to be called right before every change, further calls during the same (UI) event belong to the first one (e.g. a bulk operation moving items) */
+func (w *Board) RecordUndoStep(description string) {
+	if w.recordingUndoStep {
+		return
+	}
+
+	if data, err := w.Data(); err != nil {
+		fmt.Println(err)
+	} else {
+		w.undoSteps = append(w.undoSteps, undoStep{ description, data, append([]Tag{}, w.FilterTags...) })
+		if len(w.undoSteps) > MAX_UNDO_STEPS {
+			w.undoSteps = w.undoSteps[1:]
+		}
+		w.redoSteps = nil
+	}
+
+	w.modified          = true
+	w.recordingUndoStep = true
+	RunOnUI(w.undoStepRecorded)
+}
+
+
+/* Forgets all steps, e.g. when the board is (re)loaded */
+func (w *Board) ClearUndoHistory() {
+	w.undoSteps = nil
+	w.redoSteps = nil
+}
+
+
+func (w *Board) CanUndo() bool {
+	return len(w.undoSteps) > 0
+}
+
+
+func (w *Board) CanRedo() bool {
+	return len(w.redoSteps) > 0
+}
+
+
+/* Reverts the last recorded operation and returns its description, or an empty string if there is none */
+func (w *Board) Undo() string {
+	if !w.CanUndo() {
+		return ""
+	}
+
+	step       := w.undoSteps[len(w.undoSteps) - 1]
+	w.undoSteps = w.undoSteps[:len(w.undoSteps) - 1]
+
+	if data, err := w.Data(); err == nil {
+		w.redoSteps = append(w.redoSteps, undoStep{ step.description, data, append([]Tag{}, w.FilterTags...) })
+	}
+	w.restoreUndoStep(step)
+
+	return step.description
+}
+
+
+func (w *Board) Redo() string {
+	if !w.CanRedo() {
+		return ""
+	}
+
+	step       := w.redoSteps[len(w.redoSteps) - 1]
+	w.redoSteps = w.redoSteps[:len(w.redoSteps) - 1]
+
+	if data, err := w.Data(); err == nil {
+		w.undoSteps = append(w.undoSteps, undoStep{ step.description, data, append([]Tag{}, w.FilterTags...) })
+	}
+	w.restoreUndoStep(step)
+
+	return step.description
+}
+
+
+/* ================================================================================ Private methods */
+func (w *Board) restoreUndoStep(step undoStep) {
+	w.Clear()
+	if err := w.Load(step.data); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	w.FilterTags = step.filterTags
+	w.ApplyTagFilter()
+	if w.OnFilterChanged != nil {
+		w.OnFilterChanged(ComposeTagEditString(w.FilterTags))
+	}
+	w.modified = true
+	w.notifyChanged()
+}
+
+
+/* Ends the step once the (UI) event changing the board is over, and notifies about the change */
+func (w *Board) undoStepRecorded() {
+	w.recordingUndoStep = false
+	w.notifyChanged()
+}
+
+
+func (w *Board) notifyChanged() {
+	if w.OnChanged != nil {
+		w.OnChanged()
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+/* This file contains the watching of the board file for external changes and the comparison of two board versions */
+
+
+/* ================================================================================ Imports */
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+	"github.com/fsnotify/fsnotify"
+	"fyne.io/fyne/v2"
+)
+
+
+/* ================================================================================ Constants */
+const (
+	WATCH_DEBOUNCE_DELAY = 300 * time.Millisecond
+)
+
+
+/* ================================================================================ Public functions */
+/* Calls changedCallback (debounced, on the UI side) whenever the file is written, created or replaced, e.g. by editors saving atomically, until the returned watcher is closed */
+func WatchBoardFile(uri fyne.URI, changedCallback func()) *fsnotify.Watcher {
+	if uri == nil || uri.Scheme() != "file" {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		fmt.Println(err)
+		return nil
+	}
+
+	/* Watch the directory instead of the file, as the file watch would get lost if the file is replaced */
+	path := filepath.Clean(uri.Path())
+	if err := watcher.Add(filepath.Dir(path)); err != nil {
+		fmt.Println(err)
+		watcher.Close()
+		return nil
+	}
+
+	go func() {
+		var debounceTimer *time.Timer
+
+		for {
+			select {
+				case event, ok := <-watcher.Events:
+					if !ok {
+						return
+					}
+					if filepath.Clean(event.Name) != path || event.Op & (fsnotify.Write | fsnotify.Create | fsnotify.Rename) == 0 {
+						continue
+					}
+
+					if debounceTimer != nil {
+						debounceTimer.Stop()
+					}
+					debounceTimer = time.AfterFunc(WATCH_DEBOUNCE_DELAY, func() { RunOnUI(changedCallback) })
+
+				case err, ok := <-watcher.Errors:
+					if !ok {
+						return
+					}
+					fmt.Println(err)
+			}
+		}
+	}()
+
+	return watcher
+}
+
+
+/* Describes the differences between two versions of a board, matching items by their ID or, for files without IDs, by their title */
+func DiffBoards(oldBoard, newBoard *Board) []string {
+	differences := []string{}
+
+	if oldBoard.Name != newBoard.Name {
+		differences = append(differences, fmt.Sprintf("Board renamed from \"%s\" to \"%s\"", oldBoard.Name, newBoard.Name))
+	}
+
+	for _, stage := range oldBoard.Stages {
+		if newBoard.StageByTitle(stage.Title) == nil {
+			differences = append(differences, fmt.Sprintf("Stage \"%s\" removed", stage.Title))
+		}
+	}
+	for _, stage := range newBoard.Stages {
+		if oldBoard.StageByTitle(stage.Title) == nil {
+			differences = append(differences, fmt.Sprintf("Stage \"%s\" added", stage.Title))
+		}
+	}
+
+	matches := matchBoardItems(oldBoard, newBoard)
+	matched := map[*Item]bool{}
+
+	for _, oldStage := range oldBoard.Stages {
+		for _, oldItem := range oldStage.Items {
+			newItem := matches[oldItem]
+			if newItem == nil {
+				differences = append(differences, fmt.Sprintf("Item \"%s\" removed from \"%s\"", oldItem.Title, oldStage.Title))
+				continue
+			}
+			matched[newItem] = true
+
+			if newStage := newBoard.ItemStage(newItem); newStage.Title != oldStage.Title {
+				differences = append(differences, fmt.Sprintf("Item \"%s\" moved from \"%s\" to \"%s\"", oldItem.Title, oldStage.Title, newStage.Title))
+			}
+			if !itemContentEqual(oldItem, newItem) {
+				differences = append(differences, fmt.Sprintf("Item \"%s\" changed", oldItem.Title))
+			}
+		}
+	}
+
+	for _, newStage := range newBoard.Stages {
+		for _, newItem := range newStage.Items {
+			if !matched[newItem] {
+				differences = append(differences, fmt.Sprintf("Item \"%s\" added to \"%s\"", newItem.Title, newStage.Title))
+			}
+		}
+	}
+
+	return differences
+}
+
+
+/* ================================================================================ Private functions */
+/* Matches the items of the old board to the ones of the new board by ID, remaining ones (as items of files without IDs get IDs derived from their position) by title, preferring the same stage */
+func matchBoardItems(oldBoard, newBoard *Board) map[*Item]*Item {
+	matches   := map[*Item]*Item{}
+	unmatched := map[*Item]bool{}
+	newItems  := map[string]*Item{}
+
+	for _, stage := range newBoard.Stages {
+		for _, item := range stage.Items {
+			newItems[item.Id] = item
+			unmatched[item]   = true
+		}
+	}
+
+	for _, stage := range oldBoard.Stages {
+		for _, item := range stage.Items {
+			if newItem := newItems[item.Id]; newItem != nil && unmatched[newItem] {
+				matches[item] = newItem
+				delete(unmatched, newItem)
+			}
+		}
+	}
+
+	for _, sameStage := range []bool{ true, false } {
+		for _, oldStage := range oldBoard.Stages {
+			for _, oldItem := range oldStage.Items {
+				if matches[oldItem] != nil {
+					continue
+				}
+
+				for _, newStage := range newBoard.Stages {
+					if sameStage && newStage.Title != oldStage.Title {
+						continue
+					}
+					for _, newItem := range newStage.Items {
+						if unmatched[newItem] && matches[oldItem] == nil && newItem.Title == oldItem.Title {
+							matches[oldItem] = newItem
+							delete(unmatched, newItem)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return matches
+}
+
+
+func itemContentEqual(a, b *Item) bool {
+	if a.Title != b.Title || a.Description != b.Description || a.Style != b.Style || len(a.Tags) != len(b.Tags) {
+		return false
+	}
+
+	for i := range a.Tags {
+		if a.Tags[i] != b.Tags[i] {
+			return false
+		}
+	}
+	return true
+}
\ No newline at end of file
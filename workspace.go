@@ -0,0 +1,165 @@
+package main
+
+/* This file contains the list of recently used board files and the workspace files, which list several boards to open together */
+
+
+/* ================================================================================ Imports */
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+)
+
+
+/* ================================================================================ Constants */
+const (
+	MAX_RECENT_FILES         = 10
+	WORKSPACE_FILE_EXTENSION = ".bankanws"
+)
+
+
+/* ================================================================================ Public types */
+/* Boards are stored relative to the workspace file if possible, so workspaces can be shared along with the boards (e.g. in a repository) */
+type Workspace struct {
+	Boards []string
+	Active string `json:",omitempty"`
+}
+
+
+/* ================================================================================ Public functions */
+func PreferenceURIs(key string) []fyne.URI {
+	uris := []fyne.URI{}
+
+	for _, uriString := range strings.Split(fyne.CurrentApp().Preferences().String(key), "\n") {
+		if uri, err := storage.ParseURI(uriString); uriString != "" && err == nil {
+			uris = append(uris, uri)
+		}
+	}
+	return uris
+}
+
+
+func SetPreferenceURIs(key string, uris []fyne.URI) {
+	uriStrings := make([]string, len(uris))
+	for i, uri := range uris {
+		uriStrings[i] = uri.String()
+	}
+
+	fyne.CurrentApp().Preferences().SetString(key, strings.Join(uriStrings, "\n"))
+}
+
+
+func RecentFileURIs() []fyne.URI {
+	return PreferenceURIs("recentFileURIs")
+}
+
+
+/* Moves the file to the top of the recently used files, dropping the oldest ones beyond the maximum count */
+func AddRecentFileURI(uri fyne.URI) {
+	uris := []fyne.URI{ uri }
+
+	for _, recentURI := range RecentFileURIs() {
+		if recentURI.String() != uri.String() && len(uris) < MAX_RECENT_FILES {
+			uris = append(uris, recentURI)
+		}
+	}
+
+	SetPreferenceURIs("recentFileURIs", uris)
+}
+
+
+func RemoveRecentFileURI(uri fyne.URI) {
+	uris := []fyne.URI{}
+
+	for _, recentURI := range RecentFileURIs() {
+		if recentURI.String() != uri.String() {
+			uris = append(uris, recentURI)
+		}
+	}
+
+	SetPreferenceURIs("recentFileURIs", uris)
+}
+
+
+func ClearRecentFileURIs() {
+	SetPreferenceURIs("recentFileURIs", nil)
+}
+
+
+func IsWorkspaceURI(uri fyne.URI) bool {
+	return strings.EqualFold(uri.Extension(), WORKSPACE_FILE_EXTENSION)
+}
+
+
+/* Returns the board files listed by the workspace and the one to activate (nil if none) */
+func LoadWorkspace(data []byte, workspaceURI fyne.URI) ([]fyne.URI, fyne.URI, error) {
+	var workspace Workspace
+	if err := json.Unmarshal(data, &workspace); err != nil {
+		return nil, nil, err
+	}
+
+	boardURIs := []fyne.URI{}
+	for _, entry := range workspace.Boards {
+		uri, err := resolveWorkspaceEntry(entry, workspaceURI)
+		if err != nil {
+			return nil, nil, err
+		}
+		boardURIs = append(boardURIs, uri)
+	}
+
+	var activeURI fyne.URI
+	if workspace.Active != "" {
+		uri, err := resolveWorkspaceEntry(workspace.Active, workspaceURI)
+		if err != nil {
+			return nil, nil, err
+		}
+		activeURI = uri
+	}
+
+	return boardURIs, activeURI, nil
+}
+
+
+func WorkspaceData(workspaceURI fyne.URI, boardURIs []fyne.URI, activeURI fyne.URI) ([]byte, error) {
+	workspace := Workspace{ Boards: []string{} }
+
+	for _, uri := range boardURIs {
+		workspace.Boards = append(workspace.Boards, workspaceEntry(uri, workspaceURI))
+	}
+	if activeURI != nil {
+		workspace.Active = workspaceEntry(activeURI, workspaceURI)
+	}
+
+	return json.MarshalIndent(workspace, "", "\t")
+}
+
+
+/* ================================================================================ Private functions */
+func workspaceEntry(uri, workspaceURI fyne.URI) string {
+	if uri.Scheme() != "file" || workspaceURI.Scheme() != "file" {
+		return uri.String()
+	}
+
+	relativePath, err := filepath.Rel(filepath.Dir(workspaceURI.Path()), uri.Path())
+	if err != nil || strings.HasPrefix(relativePath, "..") {
+		return uri.String()
+	}
+
+	return filepath.ToSlash(relativePath)
+}
+
+
+func resolveWorkspaceEntry(entry string, workspaceURI fyne.URI) (fyne.URI, error) {
+	if strings.Contains(entry, "://") {
+		return storage.ParseURI(entry)
+	}
+
+	path := filepath.FromSlash(entry)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(workspaceURI.Path()), path)
+	}
+
+	return storage.NewFileURI(path), nil
+}